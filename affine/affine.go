@@ -1,58 +1,135 @@
 package affine
 
 import (
+	"bytes"
 	"fmt"
 
+	"github.com/mechanizm/jubjub"
 	"github.com/mechanizm/jubjub/fq"
 )
 
+var (
+	ErrInvalidLength = jubjub.ErrInvalidLength
+	ErrNonCanonical  = jubjub.ErrNonCanonical
+	ErrNotOnCurve    = jubjub.ErrNotOnCurve
+)
+
 // AffinePoint represents an affine point `(u, v)` on the
 // / curve `-u^2 + v^2 = 1 + d.u^2.v^2` over `Fq` with
 // / `d = -(10240/10241)`
 type AffinePoint struct {
-	U, V *fq.Fq
+	u, v *fq.Fq
+}
+
+// U returns a copy of the u-coordinate of af
+func (af *AffinePoint) U() *fq.Fq {
+	return fq.Set(af.u)
 }
 
-// Neg negates the u value in (u,v)
-// returning point (-u, v)
+// V returns a copy of the v-coordinate of af
+func (af *AffinePoint) V() *fq.Fq {
+	return fq.Set(af.v)
+}
+
+// Neg returns the point (-u, v). af is not modified.
 func (af *AffinePoint) Neg() *AffinePoint {
-	af.U = af.U.Neg()
-	return af
+	return &AffinePoint{
+		u: af.u.Neg(),
+		v: fq.Set(af.v),
+	}
 }
 
-// from_bytes_inner from_bytes
-func FromBytesInner(byt []byte) *AffinePoint {
-	sign := byt[31] >> 7
-	byt[31] &= 0b0111_1111
+// FromBytesInner decodes a point like FromBytes, but accepts
+// non-canonical encodings of v and of u = 0, as the Sapling
+// consensus rules did before ZIP 216. byt is not modified.
+func FromBytesInner(byt []byte) (*AffinePoint, error) {
+	if len(byt) != 32 {
+		return nil, ErrInvalidLength
+	}
+
+	tmp := make([]byte, 32)
+	copy(tmp, byt)
+	sign := tmp[31] >> 7
+	tmp[31] &= 0b0111_1111
 
-	v := fq.FromBytes(byt)
+	v, err := fq.FromBytes(tmp)
+	if err != nil {
+		return nil, err
+	}
 	v2 := v.Square()
 
 	t1 := v2.Sub(fq.One())
-	t2 := fq.One().Add(fq.D.Mul(v2))
-	u := (t1.Mul(t2.Inverse())).Sqrt()
+	t2 := fq.One().Add(fq.D().Mul(v2))
+	u2 := t1.Mul(t2.Inverse())
+	u := u2.Sqrt()
+	if !u.Square().Equal(u2) {
+		return nil, ErrNotOnCurve
+	}
 
 	flip := (uint64((u.Bytes())[0]) ^ uint64(sign)) & 1
 	negated := u.Neg()
 	final := fq.ConditionalSelect(u, negated, int(flip))
 	return &AffinePoint{
-		U: final,
-		V: v,
+		u: final,
+		v: v,
+	}, nil
+}
+
+// FromBytes decodes a point from its 32 byte encoding. It returns
+// ErrInvalidLength, ErrNotOnCurve, or ErrNonCanonical for encodings whose
+// v-coordinate is not canonical or which encode u = -0. byt is not
+// modified.
+func FromBytes(byt []byte) (*AffinePoint, error) {
+	if len(byt) != 32 {
+		return nil, ErrInvalidLength
+	}
+
+	tmp := make([]byte, 32)
+	copy(tmp, byt)
+	sign := tmp[31] >> 7
+	tmp[31] &= 0b0111_1111
+
+	v, err := fq.FromBytes(tmp)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(v.Bytes(), tmp) {
+		return nil, ErrNonCanonical
+	}
+
+	// u^2 = (v^2 - 1) / (1 + d.v^2), where 1 + d.v^2 is never zero
+	// since -1/d is not a square
+	v2 := v.Square()
+	u2 := v2.Sub(fq.One()).Mul(fq.One().Add(fq.D().Mul(v2)).Inverse())
+	u := u2.Sqrt()
+	if !u.Square().Equal(u2) {
+		return nil, ErrNotOnCurve
+	}
+	if u.Equal(fq.Zero()) && sign == 1 {
+		return nil, ErrNonCanonical
 	}
+
+	flip := (uint64((u.Bytes())[0]) ^ uint64(sign)) & 1
+	return &AffinePoint{
+		u: fq.ConditionalSelect(u, u.Neg(), int(flip)),
+		v: v,
+	}, nil
 }
 
+// FromRawUnchecked returns the point (u, v) without checking that it is
+// on the curve. u and v are copied, so the caller may reuse them.
 func FromRawUnchecked(u, v *fq.Fq) *AffinePoint {
 	return &AffinePoint{
-		U: u,
-		V: v,
+		u: fq.Set(u),
+		v: fq.Set(v),
 	}
 }
 
 // IntoBytes converts the af element into its little-endian
 // byte representation
 func (a *AffinePoint) Bytes() []byte {
-	tmp := a.V.Bytes()
-	u := a.U.Bytes()
+	tmp := a.v.Bytes()
+	u := a.u.Bytes()
 
 	// Encode the sign of the u-coordinate in the most
 	// significant bit.
@@ -62,5 +139,5 @@ func (a *AffinePoint) Bytes() []byte {
 }
 
 func (e *AffinePoint) String() string {
-	return fmt.Sprintf("u: %s, v: %s", e.U.String(), e.V.String())
+	return fmt.Sprintf("u: %s, v: %s", e.u.String(), e.v.String())
 }
@@ -0,0 +1,81 @@
+package affine_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/fq"
+)
+
+func TestNegDoesNotMutate(t *testing.T) {
+	byt, _ := hex.DecodeString("7d09bb9aa97704719c33d1f6e7ed7e8d6c0edad0a02f7af82ab77ebc104f5f1e")
+	p, err := affine.FromBytes(byt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := p.Neg()
+	if !bytes.Equal(p.Bytes(), byt) {
+		t.Errorf("Neg modified its receiver")
+	}
+	if !n.U().Equal(p.U().Neg()) || !n.V().Equal(p.V()) {
+		t.Errorf("Neg(P) = %s", n)
+	}
+}
+
+func TestFromBytesInnerDoesNotMutate(t *testing.T) {
+	// The sign bit is set
+	byt, _ := hex.DecodeString("7d09bb9aa97704719c33d1f6e7ed7e8d6c0edad0a02f7af82ab77ebc104f5f9e")
+	orig := append([]byte{}, byt...)
+
+	if _, err := affine.FromBytesInner(byt); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(byt, orig) {
+		t.Errorf("FromBytesInner modified its input")
+	}
+}
+
+func TestDecodingErrors(t *testing.T) {
+	// v = q + 1 is a non-canonical encoding of v = 1
+	qPlusOne, _ := hex.DecodeString("02000000fffffffffe5bfeff02a4bd5305d8a10908d83933487d9d2953a7ed73")
+	// v = 2 is not the v-coordinate of a point
+	two, _ := hex.DecodeString("0200000000000000000000000000000000000000000000000000000000000000")
+
+	vectors := []struct {
+		byt        []byte
+		err, inner error
+	}{
+		{make([]byte, 31), jubjub.ErrInvalidLength, jubjub.ErrInvalidLength},
+		{nil, jubjub.ErrInvalidLength, jubjub.ErrInvalidLength},
+		{qPlusOne, jubjub.ErrNonCanonical, nil},
+		{two, jubjub.ErrNotOnCurve, jubjub.ErrNotOnCurve},
+	}
+	for _, v := range vectors {
+		if _, err := affine.FromBytes(v.byt); !errors.Is(err, v.err) {
+			t.Errorf("FromBytes(%x) = %v, want %v", v.byt, err, v.err)
+		}
+		if _, err := affine.FromBytesInner(v.byt); !errors.Is(err, v.inner) {
+			t.Errorf("FromBytesInner(%x) = %v, want %v", v.byt, err, v.inner)
+		}
+	}
+}
+
+func TestFromRawUncheckedCopies(t *testing.T) {
+	u, v := fq.Zero(), fq.One()
+	p := affine.FromRawUnchecked(u, v)
+
+	u[0], v[0] = 1, 2
+	if !p.U().Equal(fq.Zero()) || !p.V().Equal(fq.One()) {
+		t.Errorf("FromRawUnchecked kept references to its inputs")
+	}
+
+	p.U()[0], p.V()[0] = 1, 2
+	if !p.U().Equal(fq.Zero()) || !p.V().Equal(fq.One()) {
+		t.Errorf("U and V returned references to the coordinates")
+	}
+}
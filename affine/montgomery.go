@@ -0,0 +1,156 @@
+package affine
+
+import (
+	"fmt"
+
+	"github.com/mechanizm/jubjub/fq"
+)
+
+// montgomeryA = 40962
+var montgomeryA = fq.Fq{
+	0x0001_6155_fffe_9eaa,
+	0x5f22_c400_43b2_7956,
+	0x07ae_5804_98c2_15bd,
+	0x5a70_1dad_db57_5b1c,
+}
+
+// montgomeryScale = sqrt(-40964), the root with an even canonical encoding.
+// It scales -40964.y^2 = x^3 + A.x^2 + x to y^2 = x^3 + A.x^2 + x.
+var montgomeryScale = fq.Fq{
+	0xace0_4dac_4a60_ca31,
+	0x7e5c_7c34_1d22_01b5,
+	0xe54f_4858_a681_2384,
+	0x469e_3939_d7b2_efad,
+}
+
+// MontgomeryPoint represents an affine point `(x, y)` on the
+// curve `y^2 = x^3 + A.x^2 + x` over `Fq` with `A = 40962`,
+// which is birationally equivalent to Jubjub
+type MontgomeryPoint struct {
+	X, Y *fq.Fq
+
+	// Infinity is set for the point at infinity, which is the identity
+	// and has no affine coordinates
+	Infinity bool
+}
+
+// MontgomeryInfinity returns the point at infinity
+func MontgomeryInfinity() *MontgomeryPoint {
+	return &MontgomeryPoint{
+		X:        fq.Zero(),
+		Y:        fq.Zero(),
+		Infinity: true,
+	}
+}
+
+// ToMontgomery maps af to the Montgomery curve using
+// (u, v) -> ((1 + v)/(1 - v), scale.(1 + v)/((1 - v).u)).
+// The identity maps to the point at infinity and the point
+// (0, -1) of order 2 maps to (0, 0).
+func (af *AffinePoint) ToMontgomery() *MontgomeryPoint {
+	one := fq.One()
+
+	if af.u.Equal(fq.Zero()) {
+		if af.v.Equal(one) {
+			return MontgomeryInfinity()
+		}
+		return &MontgomeryPoint{X: fq.Zero(), Y: fq.Zero()}
+	}
+
+	x := one.Add(af.v).Mul(one.Sub(af.v).Inverse())
+	y := montgomeryScale.Mul(x).Mul(af.u.Inverse())
+
+	return &MontgomeryPoint{X: x, Y: y}
+}
+
+// FromMontgomery maps m back to Jubjub using
+// (x, y) -> (scale.x/y, (x - 1)/(x + 1)).
+// The point at infinity maps to the identity and (0, 0) maps to (0, -1).
+// m must be on the curve.
+func FromMontgomery(m *MontgomeryPoint) *AffinePoint {
+	one := fq.One()
+
+	if m.Infinity {
+		return &AffinePoint{u: fq.Zero(), v: one}
+	}
+	// (0, 0) is the only point with y = 0, and x = -1 is not on the curve
+	if m.Y.Equal(fq.Zero()) {
+		return &AffinePoint{u: fq.Zero(), v: one.Neg()}
+	}
+
+	u := montgomeryScale.Mul(m.X).Mul(m.Y.Inverse())
+	v := m.X.Sub(one).Mul(m.X.Add(one).Inverse())
+
+	return &AffinePoint{u: u, v: v}
+}
+
+// IsOnCurve returns true if m satisfies y^2 = x^3 + A.x^2 + x
+func (m *MontgomeryPoint) IsOnCurve() bool {
+	if m.Infinity {
+		return true
+	}
+	rhs := m.X.Add(&montgomeryA).Mul(m.X).Add(fq.One()).Mul(m.X)
+	return m.Y.Square().Equal(rhs)
+}
+
+// Equal returns true if m and o are the same point
+func (m *MontgomeryPoint) Equal(o *MontgomeryPoint) bool {
+	if m.Infinity || o.Infinity {
+		return m.Infinity == o.Infinity
+	}
+	return m.X.Equal(o.X) && m.Y.Equal(o.Y)
+}
+
+// Neg returns (x, -y)
+func (m *MontgomeryPoint) Neg() *MontgomeryPoint {
+	if m.Infinity {
+		return MontgomeryInfinity()
+	}
+	return &MontgomeryPoint{X: fq.Set(m.X), Y: m.Y.Neg()}
+}
+
+// Add adds two points using the chord-and-tangent rule.
+// It is not constant time and is meant for cross-validation.
+func (m *MontgomeryPoint) Add(o *MontgomeryPoint) *MontgomeryPoint {
+	if m.Infinity {
+		return o.clone()
+	}
+	if o.Infinity {
+		return m.clone()
+	}
+
+	var lambda *fq.Fq
+	if m.X.Equal(o.X) {
+		if !m.Y.Equal(o.Y) || m.Y.Equal(fq.Zero()) {
+			// o = -m
+			return MontgomeryInfinity()
+		}
+		// lambda = (3.x^2 + 2.A.x + 1) / 2.y
+		xx := m.X.Square()
+		num := xx.Double().Add(xx).Add(montgomeryA.Mul(m.X).Double()).Add(fq.One())
+		lambda = num.Mul(m.Y.Double().Inverse())
+	} else {
+		lambda = o.Y.Sub(m.Y).Mul(o.X.Sub(m.X).Inverse())
+	}
+
+	x := lambda.Square().Sub(&montgomeryA).Sub(m.X).Sub(o.X)
+	y := lambda.Mul(m.X.Sub(x)).Sub(m.Y)
+
+	return &MontgomeryPoint{X: x, Y: y}
+}
+
+// Double returns m + m
+func (m *MontgomeryPoint) Double() *MontgomeryPoint {
+	return m.Add(m)
+}
+
+func (m *MontgomeryPoint) clone() *MontgomeryPoint {
+	return &MontgomeryPoint{X: fq.Set(m.X), Y: fq.Set(m.Y), Infinity: m.Infinity}
+}
+
+func (m *MontgomeryPoint) String() string {
+	if m.Infinity {
+		return "infinity"
+	}
+	return fmt.Sprintf("x: %s, y: %s", m.X.String(), m.Y.String())
+}
@@ -0,0 +1,119 @@
+package affine_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fq"
+)
+
+func testPoints(t *testing.T) []*extended.ExtendedPoint {
+	byt, err := hex.DecodeString("7d09bb9aa97704719c33d1f6e7ed7e8d6c0edad0a02f7af82ab77ebc104f5f1e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := extended.FromBytes(byt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// (0, -1) has order 2
+	t2 := extended.FromRawUnchecked(fq.Zero(), fq.One().Neg())
+
+	points := []*extended.ExtendedPoint{extended.Identity(), t2}
+	acc := b
+	for i := 0; i < 8; i++ {
+		points = append(points, acc, acc.Add(t2), acc.Neg())
+		acc = acc.Double().Add(b)
+	}
+	return points
+}
+
+func equalAffine(a, b *affine.AffinePoint) bool {
+	return a.U().Equal(b.U()) && a.V().Equal(b.V())
+}
+
+func TestMontgomeryRoundTrip(t *testing.T) {
+	for _, p := range testPoints(t) {
+		a := p.ToAffine()
+		m := a.ToMontgomery()
+		if !m.IsOnCurve() {
+			t.Errorf("%s is not on the Montgomery curve", m)
+		}
+		if back := affine.FromMontgomery(m); !equalAffine(back, a) {
+			t.Errorf("FromMontgomery(ToMontgomery(%s)) = %s", a, back)
+		}
+	}
+}
+
+func TestMontgomeryAddition(t *testing.T) {
+	points := testPoints(t)
+	for _, p := range points {
+		for _, q := range points[:6] {
+			want := p.Add(q).ToAffine().ToMontgomery()
+			got := p.ToAffine().ToMontgomery().Add(q.ToAffine().ToMontgomery())
+			if !got.Equal(want) {
+				t.Errorf("Montgomery addition mismatch: got %s, want %s", got, want)
+			}
+		}
+		want := p.Double().ToAffine().ToMontgomery()
+		if got := p.ToAffine().ToMontgomery().Double(); !got.Equal(want) {
+			t.Errorf("Montgomery doubling mismatch: got %s, want %s", got, want)
+		}
+	}
+}
+
+func TestWeierstrassRoundTrip(t *testing.T) {
+	for _, p := range testPoints(t) {
+		a := p.ToAffine()
+		w := a.ToWeierstrass()
+		if !w.IsOnCurve() {
+			t.Errorf("%s is not on the Weierstrass curve", w)
+		}
+		if back := affine.FromWeierstrass(w); !equalAffine(back, a) {
+			t.Errorf("FromWeierstrass(ToWeierstrass(%s)) = %s", a, back)
+		}
+	}
+}
+
+func TestWeierstrassAddition(t *testing.T) {
+	points := testPoints(t)
+	for _, p := range points {
+		for _, q := range points[:6] {
+			want := p.Add(q).ToAffine().ToWeierstrass()
+			got := p.ToAffine().ToWeierstrass().Add(q.ToAffine().ToWeierstrass())
+			if !got.Equal(want) {
+				t.Errorf("Weierstrass addition mismatch: got %s, want %s", got, want)
+			}
+		}
+		want := p.Double().ToAffine().ToWeierstrass()
+		if got := p.ToAffine().ToWeierstrass().Double(); !got.Equal(want) {
+			t.Errorf("Weierstrass doubling mismatch: got %s, want %s", got, want)
+		}
+	}
+}
+
+func TestExceptionalPoints(t *testing.T) {
+	identity := extended.Identity().ToAffine()
+	if !identity.ToMontgomery().Infinity || !identity.ToWeierstrass().Infinity {
+		t.Errorf("identity does not map to the point at infinity")
+	}
+	if back := affine.FromMontgomery(affine.MontgomeryInfinity()); !equalAffine(back, identity) {
+		t.Errorf("point at infinity maps to %s", back)
+	}
+
+	t2 := affine.FromRawUnchecked(fq.Zero(), fq.One().Neg())
+	m := t2.ToMontgomery()
+	if m.Infinity || !m.X.Equal(fq.Zero()) || !m.Y.Equal(fq.Zero()) {
+		t.Errorf("(0, -1) maps to %s, want (0, 0)", m)
+	}
+	if !m.Double().Infinity {
+		t.Errorf("(0, 0) does not have order 2")
+	}
+	if back := affine.FromMontgomery(m); !bytes.Equal(back.Bytes(), t2.Bytes()) {
+		t.Errorf("(0, 0) maps to %s, want (0, -1)", back)
+	}
+}
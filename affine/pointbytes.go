@@ -0,0 +1,59 @@
+package affine
+
+import "encoding/hex"
+
+// PointBytes is the 32 byte encoding of a point: the v-coordinate in
+// little-endian with the sign of u in the top bit. It is comparable, so
+// it can be used as a map key.
+type PointBytes [32]byte
+
+// PointBytes returns the encoding of a
+func (a *AffinePoint) PointBytes() PointBytes {
+	var b PointBytes
+	copy(b[:], a.Bytes())
+	return b
+}
+
+// FromPointBytes decodes a point with the checks of FromBytes
+func FromPointBytes(b PointBytes) (*AffinePoint, error) {
+	return FromBytes(b[:])
+}
+
+// PointBytesFromHex parses the hex encoding of 32 bytes
+func PointBytesFromHex(s string) (PointBytes, error) {
+	var b PointBytes
+	err := b.UnmarshalText([]byte(s))
+	return b, err
+}
+
+func (b PointBytes) String() string {
+	return hex.EncodeToString(b[:])
+}
+
+func (b PointBytes) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+func (b *PointBytes) UnmarshalText(text []byte) error {
+	if len(text) != 2*len(b) {
+		return ErrInvalidLength
+	}
+	var tmp PointBytes
+	if _, err := hex.Decode(tmp[:], text); err != nil {
+		return err
+	}
+	*b = tmp
+	return nil
+}
+
+func (b PointBytes) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, b[:]...), nil
+}
+
+func (b *PointBytes) UnmarshalBinary(data []byte) error {
+	if len(data) != len(b) {
+		return ErrInvalidLength
+	}
+	copy(b[:], data)
+	return nil
+}
@@ -0,0 +1,85 @@
+package affine_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/extended"
+)
+
+const testEncoding = "7d09bb9aa97704719c33d1f6e7ed7e8d6c0edad0a02f7af82ab77ebc104f5f1e"
+
+func TestPointBytesRoundTrip(t *testing.T) {
+	b, err := affine.PointBytesFromHex(testEncoding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != testEncoding {
+		t.Errorf("String() = %s, want %s", b, testEncoding)
+	}
+
+	p, err := affine.FromPointBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PointBytes() != b {
+		t.Errorf("PointBytes() = %s, want %s", p.PointBytes(), b)
+	}
+
+	e, err := extended.FromPointBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.PointBytes() != b || e.Double().PointBytes() == b {
+		t.Errorf("extended PointBytes does not round trip")
+	}
+}
+
+func TestPointBytesMarshaling(t *testing.T) {
+	b, _ := affine.PointBytesFromHex(testEncoding)
+
+	text, err := json.Marshal(map[string]affine.PointBytes{"p": b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(text) != `{"p":"`+testEncoding+`"}` {
+		t.Errorf("json.Marshal = %s", text)
+	}
+	var decoded map[string]affine.PointBytes
+	if err := json.Unmarshal(text, &decoded); err != nil || decoded["p"] != b {
+		t.Errorf("json.Unmarshal = %v, %v", decoded, err)
+	}
+
+	bin, _ := b.MarshalBinary()
+	var fromBin affine.PointBytes
+	if err := fromBin.UnmarshalBinary(bin); err != nil || fromBin != b {
+		t.Errorf("UnmarshalBinary = %s, %v", fromBin, err)
+	}
+
+	// PointBytes is comparable and can be a map key
+	seen := map[affine.PointBytes]bool{b: true}
+	if !seen[fromBin] {
+		t.Errorf("equal PointBytes are different map keys")
+	}
+}
+
+func TestPointBytesInvalid(t *testing.T) {
+	var b affine.PointBytes
+	if err := b.UnmarshalBinary(make([]byte, 31)); !errors.Is(err, affine.ErrInvalidLength) {
+		t.Errorf("UnmarshalBinary: expected ErrInvalidLength, got %v", err)
+	}
+	if _, err := affine.PointBytesFromHex(testEncoding[2:]); !errors.Is(err, affine.ErrInvalidLength) {
+		t.Errorf("PointBytesFromHex: expected ErrInvalidLength, got %v", err)
+	}
+	if _, err := affine.PointBytesFromHex("zz" + testEncoding[2:]); err == nil {
+		t.Errorf("PointBytesFromHex accepted invalid hex")
+	}
+
+	// v = 2 is not on the curve
+	b[0] = 2
+	if _, err := affine.FromPointBytes(b); !errors.Is(err, affine.ErrNotOnCurve) {
+		t.Errorf("FromPointBytes: expected ErrNotOnCurve, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package affine
+
+import (
+	"fmt"
+
+	"github.com/mechanizm/jubjub/fq"
+)
+
+// aDiv3 = A/3, the shift between the Montgomery and Weierstrass x-coordinates
+var aDiv3 = fq.Fq{
+	0x0000_75c6_ffff_8a39,
+	0x3ba0_22ab_c13a_f1c7,
+	0xbe4d_6559_8b76_a496,
+	0x44c9_ec55_ac51_9d76,
+}
+
+// weierstrassA = 1 - A^2/3
+var weierstrassA = fq.Fq{
+	0xb662_5582_499d_aa7e,
+	0x9717_3eb3_0aab_9d81,
+	0x679a_b935_3741_4b4d,
+	0x34f4_02e0_7c98_84a1,
+}
+
+// weierstrassB = A.(2.A^2 - 9)/27
+var weierstrassB = fq.Fq{
+	0x94d7_5128_6b28_a49e,
+	0x1e5b_a844_e6c4_2d9b,
+	0x4845_7b3b_39bc_cf70,
+	0x1eae_ab99_769b_7f83,
+}
+
+// WeierstrassPoint represents an affine point `(x, y)` on the
+// short Weierstrass curve `y^2 = x^3 + a.x + b` over `Fq` with
+// `a = 1 - A^2/3` and `b = A.(2.A^2 - 9)/27`, `A = 40962`
+type WeierstrassPoint struct {
+	X, Y *fq.Fq
+
+	// Infinity is set for the point at infinity, which is the identity
+	Infinity bool
+}
+
+// WeierstrassInfinity returns the point at infinity
+func WeierstrassInfinity() *WeierstrassPoint {
+	return &WeierstrassPoint{
+		X:        fq.Zero(),
+		Y:        fq.Zero(),
+		Infinity: true,
+	}
+}
+
+// ToWeierstrass maps af to the short Weierstrass curve, going through
+// the Montgomery form: (x, y) -> (x + A/3, y)
+func (af *AffinePoint) ToWeierstrass() *WeierstrassPoint {
+	return af.ToMontgomery().ToWeierstrass()
+}
+
+// FromWeierstrass maps w back to Jubjub. w must be on the curve.
+func FromWeierstrass(w *WeierstrassPoint) *AffinePoint {
+	return FromMontgomery(w.ToMontgomery())
+}
+
+// ToWeierstrass maps m to the short Weierstrass curve
+func (m *MontgomeryPoint) ToWeierstrass() *WeierstrassPoint {
+	if m.Infinity {
+		return WeierstrassInfinity()
+	}
+	return &WeierstrassPoint{X: m.X.Add(&aDiv3), Y: fq.Set(m.Y)}
+}
+
+// ToMontgomery maps w to the Montgomery curve
+func (w *WeierstrassPoint) ToMontgomery() *MontgomeryPoint {
+	if w.Infinity {
+		return MontgomeryInfinity()
+	}
+	return &MontgomeryPoint{X: w.X.Sub(&aDiv3), Y: fq.Set(w.Y)}
+}
+
+// IsOnCurve returns true if w satisfies y^2 = x^3 + a.x + b
+func (w *WeierstrassPoint) IsOnCurve() bool {
+	if w.Infinity {
+		return true
+	}
+	rhs := w.X.Square().Add(&weierstrassA).Mul(w.X).Add(&weierstrassB)
+	return w.Y.Square().Equal(rhs)
+}
+
+// Equal returns true if w and o are the same point
+func (w *WeierstrassPoint) Equal(o *WeierstrassPoint) bool {
+	if w.Infinity || o.Infinity {
+		return w.Infinity == o.Infinity
+	}
+	return w.X.Equal(o.X) && w.Y.Equal(o.Y)
+}
+
+// Neg returns (x, -y)
+func (w *WeierstrassPoint) Neg() *WeierstrassPoint {
+	if w.Infinity {
+		return WeierstrassInfinity()
+	}
+	return &WeierstrassPoint{X: fq.Set(w.X), Y: w.Y.Neg()}
+}
+
+// Add adds two points using the chord-and-tangent rule.
+// It is not constant time and is meant for cross-validation.
+func (w *WeierstrassPoint) Add(o *WeierstrassPoint) *WeierstrassPoint {
+	if w.Infinity {
+		return o.clone()
+	}
+	if o.Infinity {
+		return w.clone()
+	}
+
+	var lambda *fq.Fq
+	if w.X.Equal(o.X) {
+		if !w.Y.Equal(o.Y) || w.Y.Equal(fq.Zero()) {
+			// o = -w
+			return WeierstrassInfinity()
+		}
+		// lambda = (3.x^2 + a) / 2.y
+		xx := w.X.Square()
+		num := xx.Double().Add(xx).Add(&weierstrassA)
+		lambda = num.Mul(w.Y.Double().Inverse())
+	} else {
+		lambda = o.Y.Sub(w.Y).Mul(o.X.Sub(w.X).Inverse())
+	}
+
+	x := lambda.Square().Sub(w.X).Sub(o.X)
+	y := lambda.Mul(w.X.Sub(x)).Sub(w.Y)
+
+	return &WeierstrassPoint{X: x, Y: y}
+}
+
+// Double returns w + w
+func (w *WeierstrassPoint) Double() *WeierstrassPoint {
+	return w.Add(w)
+}
+
+func (w *WeierstrassPoint) clone() *WeierstrassPoint {
+	return &WeierstrassPoint{X: fq.Set(w.X), Y: fq.Set(w.Y), Infinity: w.Infinity}
+}
+
+func (w *WeierstrassPoint) String() string {
+	if w.Infinity {
+		return "infinity"
+	}
+	return fmt.Sprintf("x: %s, y: %s", w.X.String(), w.Y.String())
+}
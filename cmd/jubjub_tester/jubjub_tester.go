@@ -5,7 +5,6 @@ import (
 	"log"
 
 	"github.com/mechanizm/jubjub/extended"
-	"github.com/mechanizm/jubjub/fr"
 )
 
 func main() {
@@ -14,9 +13,11 @@ func main() {
 		panic(err)
 	}
 
-	point := extended.FromBytes(addressBytes)
-	// mod_bytes := []byte{14, 125, 180, 234, 101, 51, 175, 169, 6, 103, 59, 1, 1, 52, 59, 0, 166, 104, 32, 147, 204, 200, 16, 130, 208, 151, 14, 94, 214, 247, 44, 183}
-	point = point.Mul(fr.MODULUS.BytesNotCanonical())
+	point, err := extended.FromBytes(addressBytes)
+	if err != nil {
+		panic(err)
+	}
 
-	log.Printf("[DEBUG] point is %v, is identity %t", point.StringNotCanonical(), point.IsIdentity())
+	// [r]point is the identity for points of the prime-order subgroup
+	log.Printf("[DEBUG] point is %v, is torsion free %t", point.StringNotCanonical(), point.IsTorsionFree())
 }
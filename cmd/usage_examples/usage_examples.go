@@ -110,21 +110,8 @@ func main() {
 	*/
 
 	/*
-	committer, err := windowedpedersencommit.NewCommitter()
-	if err != nil {
-		panic(err)
-	}
-	r := big.NewInt(0)
-	r.SetString("064456a620a0415423b0e2659b7db333389ab691eb330d2d3e1ed103c47ad918", 16)
-	p, err := committer.Commit(
-		[]bool{true, true, true, true, true, true},
-		[]bool{false, false, false, false, true, false, false, true, false, true, true, true, false, false, true, false, false, false, false, false, false, false, false, false, true, true, true, true, false, true, false, false, false, false, false, false, true, false, false, false, false, false, true, true, true, false, false, false, false, true, true, true, true, false, true, true, true, true, false, true, false, true, false, false, false, false, false, true, true, true, true, true, true, true, true, true, false, true, true, true, false, false, true, false, false, false, true, true, true, true, false, false, false, false, true, false, false, false, true, false, false, true, true, false, false, false, false, true, false, false, true, false, false, false, false, true, true, true, true, true, false, false, true, false, false, true, false, true, true, false, false, true, true, true, true, false, false, false, true, false, false, true, false, true, true, false, true, true, true, true, true, false, true, true, false, false, true, false, false, true, false, true, false, false, true, false, true, true, false, false, true, true, false, true, true, false, true, true, true, true, false, false, false, false, true, false, true, false, false, false, true, true, true, true, true, true, true, false, false, false, false, true, false, true, false, false, true, true, false, true, false, false, true, true, false, true, false, false, true, false, false, false, false, false, true, true, true, true, false, true, false, true, false, false, true, true, false, false, false, false, false, true, false, true, false, false, true, true, true, true, false, true, true, false, true, true, true, false, true, false, true, true, true, false, false, true, false, false, true, true, false, false, true, true, false, false, false, false, false, true, true, false, false, true, true, false, true, false, false, false, false, false, true, true, false, true, true, true, false, false, true, false, true, true, true, false, false, true, false, false, false, true, false, false, false, true, true, false, false, false, true, true, true, false, false, true, true, true, true, false, true, true, true, true, false, true, false, false, true, true, true, false, false, false, true, true, false, true, false, true, false, true, false, false, true, false, false, true, false, true, false, true, true, true, true, false, false, false, false, true, true, false, false, false, false, false, true, true, false, true, true, true, true, true, true, true, false, false, true, false, true, true, true, true, false, true, true, true, true, false, false, false, false, false, false, false, false, true, false, true, false, false, false, false, false, false, false, false, true, false, false, false, true, false, true, true, true, true, false, false, false, false, false, true, true, true, true, false, true, true, false, true, true, true, false, false, false, true, false, true, false, false, true, false, true, true, false, false, true, true, true, false, false, false, false, false, true, false, true, true, false, true, true, true, false, true, false, true, true, false, true, false, true, true, false, true, true, false, true, false, true, true, true, false, true, false, true, false, false, true, true, false, false, true, false, true, true, false, false, false, true, false, true, true, true, false, true, true, true, false, true, true, true, false, false, false, false, true, false, false, true, false, false, false, false, false, false, true, true, false, true, false, true, true, false, false, false, true, true, false, true, true, false, true, true, true, true, false, true, true, true, false, false, false, false, true, true, false, true, false, false, false, false, false, false, false},
-		r,
-	)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Printf("commitment: %v\n", p)
+	cmu := windowedpedersencommit.NoteCommit(gd, pkd, value, rcm)
+	fmt.Printf("cmu: %x\n", cmu.Bytes())
 	*/
 
 	committer, err := homomorphicpedersencommit.NewCommitter()
@@ -0,0 +1,56 @@
+package decaf
+
+import "github.com/mechanizm/jubjub/fq"
+
+// All constants are in Montgomery form.
+
+// sqrtM1 = sqrt(-1), used to rotate by the 4-torsion point (sqrt(-1), 0)
+var sqrtM1 = fq.Fq{
+	0xf3b0_5674_aa89_cfb1,
+	0x072f_0140_6006_b9fe,
+	0xce9a_0dbf_2566_7a26,
+	0x4d2c_e405_2d59_8374,
+}
+
+// invSqrtAMinusD = 1/sqrt(a - d) with a = -1
+var invSqrtAMinusD = fq.Fq{
+	0xa98f_d929_5acf_9ae8,
+	0x6ab0_93e7_716e_2d24,
+	0x26f5_47d7_b190_5a40,
+	0x16a7_b70c_a8f5_46cd,
+}
+
+// sqrtADMinusOne = sqrt(a.d - 1) with a = -1, the negative root as in
+// ristretto255. The sign of the output of the Elligator map depends on it.
+var sqrtADMinusOne = fq.Fq{
+	0x8db1_d18e_7b54_3f1b,
+	0xf992_b658_9838_9eb4,
+	0xab6b_cfec_d339_6eb3,
+	0x3431_c47b_af27_c9dc,
+}
+
+// oneMinusDSq = 1 - d^2
+var oneMinusDSq = fq.Fq{
+	0xdcbf_d2bf_d18f_b97c,
+	0xe65b_e6ca_cb73_82a2,
+	0x833c_5ab5_17a5_e155,
+	0x2ee1_9c7c_0b32_f0ec,
+}
+
+// dMinusOneSq = (d - 1)^2
+var dMinusOneSq = fq.Fq{
+	0xce9b_e496_bb86_5922,
+	0x794f_3d51_195a_2ff8,
+	0x223d_fe11_502a_1cfb,
+	0x3951_278b_a575_c826,
+}
+
+// zeta = 7, the non-square used by sqrtRatio and the Elligator map.
+// Ristretto255 uses sqrt(-1) here, but q = 1 mod 8 for Jubjub, so
+// sqrt(-1) is itself a square and cannot be used.
+var zeta = fq.Fq{
+	0x0000_000e_ffff_fff1,
+	0x17e3_63d3_0018_9c0f,
+	0xff9c_5787_6f84_57b0,
+	0x3513_3220_8fc5_a8c4,
+}
@@ -0,0 +1,285 @@
+// Package decaf implements a prime-order group on top of Jubjub using the
+// Ristretto construction, the cofactor 8 variant of Decaf.
+//
+// Jubjub has cofactor 8 and a cyclic 8-torsion subgroup, so the group
+// [2]E / E[4] has prime order r. A Point is a representative of one of
+// these classes: encodings are canonical, Equal ignores the torsion
+// component and every valid encoding decodes to an element of the
+// prime-order group, so protocols built on this package never need to
+// multiply by the cofactor or check subgroup membership.
+package decaf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/blake2b"
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fq"
+	"github.com/mechanizm/jubjub/fr"
+)
+
+var (
+	ErrInvalidLength = jubjub.ErrInvalidLength
+	ErrNonCanonical  = jubjub.ErrNonCanonical
+	ErrNotOnCurve    = jubjub.ErrNotOnCurve
+
+	// ErrInvalidEncoding is returned by Decode for strings that are not
+	// the output of Bytes. The error also matches the underlying reason,
+	// ErrNonCanonical or ErrNotOnCurve.
+	ErrInvalidEncoding = errors.New("decaf: invalid encoding")
+
+	// ErrNotInImage is returned by FromExtended for points outside [2]E,
+	// which have no class in the group.
+	ErrNotInImage = errors.New("decaf: point is not in [2]E")
+)
+
+// invalidEncodingError wraps the reason why an encoding was rejected
+type invalidEncodingError struct {
+	err error
+}
+
+func (e *invalidEncodingError) Error() string {
+	return ErrInvalidEncoding.Error() + ": " + e.err.Error()
+}
+
+func (e *invalidEncodingError) Unwrap() error {
+	return e.err
+}
+
+func (e *invalidEncodingError) Is(target error) bool {
+	return target == ErrInvalidEncoding
+}
+
+// Point is an element of the prime-order group
+type Point struct {
+	ep *extended.ExtendedPoint
+}
+
+// Identity returns the identity element, encoded as 32 zero bytes
+func Identity() *Point {
+	return &Point{ep: extended.Identity()}
+}
+
+// Decode decodes a 32 byte encoding into a Point. It rejects
+// every string that is not the output of Bytes.
+func Decode(byt []byte) (*Point, error) {
+	if len(byt) != 32 {
+		return nil, ErrInvalidLength
+	}
+
+	s, err := fq.FromBytes(byt)
+	if err != nil {
+		return nil, err
+	}
+	// s must be canonical and non-negative
+	if !bytes.Equal(s.Bytes(), byt) || isNegative(s) == 1 {
+		return nil, &invalidEncodingError{err: ErrNonCanonical}
+	}
+
+	one := fq.One()
+	ss := s.Square()
+	u1 := one.Sub(ss)
+	u2 := one.Add(ss)
+	u2Sqr := u2.Square()
+
+	v := fq.D().Mul(u1.Square()).Neg().Sub(u2Sqr)
+	wasSquare, invSqrt := sqrtRatio(one, v.Mul(u2Sqr))
+
+	denU := invSqrt.Mul(u2)
+	denV := invSqrt.Mul(denU).Mul(v)
+
+	u := abs(s.Double().Mul(denU))
+	w := u1.Mul(denV)
+	t := u.Mul(w)
+
+	if wasSquare == 0 || isNegative(t) == 1 || w.Equal(fq.Zero()) {
+		return nil, &invalidEncodingError{err: ErrNotOnCurve}
+	}
+
+	return &Point{ep: extended.FromAffine(affine.FromRawUnchecked(u, w))}, nil
+}
+
+// uniformPersonalization is the BLAKE2b personalization with which
+// FromUniformBytes expands its input
+var uniformPersonalization = []byte("JubjubDecaf_Unif")
+
+// FromUniformBytes maps 64 uniformly random bytes to a Point. The map is
+// one-way: the discrete log of the output is unknown.
+//
+// Reducing each 32-byte half modulo q, as ristretto255 does, would be
+// biased: q is about 0.45 * 2^256, so some field elements would be hit
+// three times and the others twice. Instead the input is expanded into
+// BLAKE2b-512(0x00 || byt) and BLAKE2b-512(0x01 || byt). Each 64-byte
+// digest is reduced modulo q, which leaves a negligible bias, and sent
+// through the Elligator map, and the two results are added.
+func FromUniformBytes(byt []byte) (*Point, error) {
+	if len(byt) != 64 {
+		return nil, ErrInvalidLength
+	}
+
+	acc := extended.Identity()
+	for i := byte(0); i < 2; i++ {
+		h, err := blake2b.New512WithPersonalization(nil, uniformPersonalization)
+		if err != nil {
+			return nil, err
+		}
+		h.Write([]byte{i})
+		h.Write(byt)
+
+		t, err := fq.FromBytesWide(h.Sum(nil))
+		if err != nil {
+			return nil, err
+		}
+		acc = acc.Add(elligator(t))
+	}
+
+	return &Point{ep: acc}, nil
+}
+
+// FromExtended returns the class of e. e must lie in [2]E, which is the
+// case for any point of the prime-order subgroup, otherwise ErrNotInImage
+// is returned. The check costs a scalar multiplication.
+func FromExtended(e *extended.ExtendedPoint) (*Point, error) {
+	// The 8-torsion subgroup is cyclic, so e is in [2]E exactly when its
+	// torsion component is an even multiple of the generator
+	if e.TorsionIndex()%2 != 0 {
+		return nil, ErrNotInImage
+	}
+	return &Point{ep: e}, nil
+}
+
+// Extended returns a representative of p on the curve. The
+// representative may differ from p by a point of order 4.
+func (p *Point) Extended() *extended.ExtendedPoint {
+	return p.ep
+}
+
+// Bytes returns the canonical 32 byte encoding of p
+func (p *Point) Bytes() []byte {
+	a := p.ep.ToAffine()
+	u0, v0 := a.U(), a.V()
+	t0 := u0.Mul(v0)
+
+	one := fq.One()
+	u1 := one.Add(v0).Mul(one.Sub(v0))
+	u2 := u0.Mul(v0)
+
+	_, invSqrt := sqrtRatio(one, u1.Mul(u2.Square()))
+
+	den1 := invSqrt.Mul(u1)
+	den2 := invSqrt.Mul(u2)
+	zInv := den1.Mul(den2).Mul(t0)
+
+	iu0 := u0.Mul(&sqrtM1)
+	iv0 := v0.Mul(&sqrtM1)
+	enchantedDenominator := den1.Mul(&invSqrtAMinusD)
+
+	// Rotate by the 4-torsion point, so that the representative
+	// chosen does not depend on the torsion component of p.
+	rotate := isNegative(t0.Mul(zInv))
+	u := fq.ConditionalSelect(u0, iv0, rotate)
+	v := fq.ConditionalSelect(v0, iu0, rotate)
+	denInv := fq.ConditionalSelect(den2, enchantedDenominator, rotate)
+
+	v = fq.ConditionalSelect(v, v.Neg(), isNegative(u.Mul(zInv)))
+
+	s := abs(denInv.Mul(one.Sub(v)))
+	return s.Bytes()
+}
+
+// Equal returns true if p and q are the same group element, that is
+// if they differ at most by a point of order 4.
+func (p *Point) Equal(q *Point) bool {
+	a := p.ep.ToAffine()
+	b := q.ep.ToAffine()
+	au, av, bu, bv := a.U(), a.V(), b.U(), b.V()
+
+	return au.Mul(bv).Equal(av.Mul(bu)) || av.Mul(bv).Equal(au.Mul(bu))
+}
+
+// IsIdentity returns true if p is the identity element
+func (p *Point) IsIdentity() bool {
+	return p.Equal(Identity())
+}
+
+func (p *Point) Add(q *Point) *Point {
+	return &Point{ep: p.ep.Add(q.ep)}
+}
+
+func (p *Point) Sub(q *Point) *Point {
+	return &Point{ep: p.ep.Sub(q.ep)}
+}
+
+func (p *Point) Neg() *Point {
+	return &Point{ep: p.ep.Neg()}
+}
+
+func (p *Point) Double() *Point {
+	return &Point{ep: p.ep.Double()}
+}
+
+// Mul returns [s]p
+func (p *Point) Mul(s *fr.Fr) *Point {
+	return &Point{ep: p.ep.Mul(s)}
+}
+
+func (p *Point) String() string {
+	return hex.EncodeToString(p.Bytes())
+}
+
+// elligator maps a field element to a point of [2]E
+func elligator(t *fq.Fq) *extended.ExtendedPoint {
+	one := fq.One()
+	r := zeta.Mul(t.Square())
+	u := r.Add(one).Mul(&oneMinusDSq)
+	v := one.Neg().Sub(r.Mul(fq.D())).Mul(r.Add(fq.D()))
+
+	wasSquare, s := sqrtRatio(u, v)
+	sPrime := abs(s.Mul(t)).Neg()
+	s = fq.ConditionalSelect(sPrime, s, wasSquare)
+	c := fq.ConditionalSelect(r, one.Neg(), wasSquare)
+
+	n := c.Mul(r.Sub(one)).Mul(&dMinusOneSq).Sub(v)
+	ss := s.Square()
+
+	w0 := s.Double().Mul(v)
+	w1 := n.Mul(&sqrtADMinusOne)
+	w2 := one.Sub(ss)
+	w3 := one.Add(ss)
+
+	// The point is (w0.w3 : w2.w1 : w1.w3) in projective coordinates
+	zInv := w1.Mul(w3).Inverse()
+	return extended.FromRawUnchecked(w0.Mul(w3).Mul(zInv), w2.Mul(w1).Mul(zInv))
+}
+
+// sqrtRatio returns (1, sqrt(u/v)) if u/v is a square and
+// (0, sqrt(zeta.u/v)) otherwise. The root returned is never negative.
+func sqrtRatio(u, v *fq.Fq) (int, *fq.Fq) {
+	w := u.Mul(v.Inverse())
+	r := w.Sqrt()
+
+	wasSquare := 0
+	if r.Square().Equal(w) {
+		wasSquare = 1
+	}
+	// u/0 is not defined unless u is also zero
+	if v.Equal(fq.Zero()) && !u.Equal(fq.Zero()) {
+		wasSquare = 0
+	}
+
+	r = fq.ConditionalSelect(zeta.Mul(w).Sqrt(), r, wasSquare)
+	return wasSquare, abs(r)
+}
+
+// isNegative returns 1 if the canonical encoding of f is odd
+func isNegative(f *fq.Fq) int {
+	return int(f.Bytes()[0] & 1)
+}
+
+func abs(f *fq.Fq) *fq.Fq {
+	return fq.ConditionalSelect(f, f.Neg(), isNegative(f))
+}
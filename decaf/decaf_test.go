@@ -0,0 +1,426 @@
+package decaf
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/blake2b"
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fq"
+	"github.com/mechanizm/jubjub/fr"
+	"github.com/mechanizm/jubjub/futil"
+)
+
+// Encodings of [k]B for k = 0..7, where B is the decoding of the
+// smallest valid encoding (s = 10).
+var multiplesOfB = []string{
+	"0000000000000000000000000000000000000000000000000000000000000000",
+	"0a00000000000000000000000000000000000000000000000000000000000000",
+	"8c14abbfb9626e7728baf1312c4ba412f8a4769e4e59d9a30c984d6d259d352e",
+	"ea68f0aac44f75a90a909cd536a440ff4742c7778dcbe8f4fbe0111d5cd29518",
+	"28a8772dd3e163b131948864afcdfe2963229b22626cccb349d50befcd013546",
+	"a2dda9576fb2b11dad36bc20ce1e916ef2c66721336f5fbed79cc7feab37c900",
+	"feca4a79651556cfbfacc15b74bd73254da7bc53673b78ff3334f6f0cfdaf20a",
+	"3ae0fc62bfcec8ea4e226a8dbc5b48b14c66c85ff64b908c698aa38bbb210048",
+}
+
+// FromUniformBytes(SHA-512(label)). These are regression vectors
+// generated by this package.
+var uniformVectors = []struct {
+	label, encoding string
+}{
+	{"", "52aa8540c21712ecd872eb230c7591ec737c1ebc83e1e823903a68749959de33"},
+	{"Jubjub", "58af82e43d695b0799fd137f82167f9278c891f5d52b8d4e4e6f0c109ee80006"},
+	{"decaf", "b6d6544b5aae7531ffc1472e9d5735f4b6b7da78eec7afee50f8b0da88bb4e27"},
+}
+
+func mustDecode(t *testing.T, s string) *Point {
+	byt, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Decode(byt)
+	if err != nil {
+		t.Fatalf("Decode(%s): %v", s, err)
+	}
+	return p
+}
+
+func scalar(k uint64) *fr.Fr {
+	byt := make([]byte, 32)
+	for i := 0; i < 8; i++ {
+		byt[i] = byte(k >> (8 * i))
+	}
+	s, _ := fr.FromBytes(byt)
+	return s
+}
+
+func TestMultiples(t *testing.T) {
+	b := mustDecode(t, multiplesOfB[1])
+	acc := Identity()
+	for k, want := range multiplesOfB {
+		if got := hex.EncodeToString(acc.Bytes()); got != want {
+			t.Errorf("[%d]B by addition = %s, want %s", k, got, want)
+		}
+		if got := hex.EncodeToString(b.Mul(scalar(uint64(k))).Bytes()); got != want {
+			t.Errorf("[%d]B by Mul = %s, want %s", k, got, want)
+		}
+		if !mustDecode(t, want).Equal(acc) {
+			t.Errorf("decoding of [%d]B is not equal to [%d]B", k, k)
+		}
+		acc = acc.Add(b)
+	}
+}
+
+func uniformInput(label string) []byte {
+	h := sha512.Sum512([]byte(label))
+	return h[:]
+}
+
+func TestUniformBytes(t *testing.T) {
+	for _, v := range uniformVectors {
+		p, err := FromUniformBytes(uniformInput(v.label))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(p.Bytes()); got != v.encoding {
+			t.Errorf("FromUniformBytes(%q) = %s, want %s", v.label, got, v.encoding)
+		}
+	}
+
+	for _, n := range []int{0, 32, 128} {
+		if _, err := FromUniformBytes(make([]byte, n)); err != ErrInvalidLength {
+			t.Errorf("%d bytes: expected ErrInvalidLength, got %v", n, err)
+		}
+	}
+}
+
+func TestTorsionIsIgnored(t *testing.T) {
+	// (sqrt(-1), 0) has order 4 and generates E[4]
+	t4 := extended.FromRawUnchecked(&sqrtM1, fq.Zero())
+
+	p := mustDecode(t, multiplesOfB[3])
+	enc := p.Bytes()
+
+	q := p.Extended()
+	for i := 0; i < 4; i++ {
+		pt, err := FromExtended(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pt.Equal(p) {
+			t.Errorf("P + [%d]T4 is not equal to P", i)
+		}
+		if !bytes.Equal(pt.Bytes(), enc) {
+			t.Errorf("P + [%d]T4 has a different encoding", i)
+		}
+		q = q.Add(t4)
+	}
+}
+
+func TestFromExtendedRejectsOddTorsion(t *testing.T) {
+	p := mustDecode(t, multiplesOfB[3]).Extended()
+	for k, tk := range extended.EightTorsion() {
+		_, err := FromExtended(p.Add(tk))
+		if k%2 == 0 && err != nil {
+			t.Errorf("P + [%d]T: %v", k, err)
+		}
+		if k%2 == 1 && err != ErrNotInImage {
+			t.Errorf("P + [%d]T: expected ErrNotInImage, got %v", k, err)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	b := mustDecode(t, multiplesOfB[1])
+	for i := 0; i < 16; i++ {
+		h := sha512.Sum512([]byte{byte(i)})
+		s, _ := fr.FromBytesWide(h[:])
+		p := b.Mul(s)
+
+		q, err := Decode(p.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !q.Equal(p) || !bytes.Equal(q.Bytes(), p.Bytes()) {
+			t.Errorf("round trip failed for %s", p)
+		}
+		if !p.Sub(q).IsIdentity() {
+			t.Errorf("P - Decode(P.Bytes()) is not the identity")
+		}
+	}
+}
+
+func TestRejectsNonCanonical(t *testing.T) {
+	// q + 10 is a non-canonical encoding of s = 10
+	qPlus10, _ := hex.DecodeString("0b000000fffffffffe5bfeff02a4bd5305d8a10908d83933487d9d2953a7ed73")
+	// -10 is a negative field element
+	minus10, _ := hex.DecodeString("f7fffffffefffffffe5bfeff02a4bd5305d8a10908d83933487d9d2953a7ed73")
+	// s = 2 is not the encoding of any point
+	two, _ := hex.DecodeString("0200000000000000000000000000000000000000000000000000000000000000")
+	ff := bytes.Repeat([]byte{0xff}, 32)
+
+	for _, tc := range []struct {
+		byt    []byte
+		reason error
+	}{
+		{qPlus10, jubjub.ErrNonCanonical},
+		{minus10, jubjub.ErrNonCanonical},
+		{two, jubjub.ErrNotOnCurve},
+		{ff, jubjub.ErrNonCanonical},
+	} {
+		_, err := Decode(tc.byt)
+		if !errors.Is(err, ErrInvalidEncoding) || !errors.Is(err, tc.reason) {
+			t.Errorf("Decode(%x) = %v, want ErrInvalidEncoding and %v", tc.byt, err, tc.reason)
+		}
+	}
+
+	if _, err := Decode(make([]byte, 31)); err != ErrInvalidLength {
+		t.Errorf("expected ErrInvalidLength, got %v", err)
+	}
+}
+
+func TestDecodedAreCanonical(t *testing.T) {
+	// Every string that decodes must re-encode to itself
+	byt := make([]byte, 32)
+	decoded := 0
+	for s := 0; s < 512; s++ {
+		byt[0], byt[1] = byte(s), byte(s>>8)
+		p, err := Decode(byt)
+		if err != nil {
+			continue
+		}
+		decoded++
+		if !bytes.Equal(p.Bytes(), byt) {
+			t.Errorf("%x decodes but encodes to %x", byt, p.Bytes())
+		}
+	}
+	if decoded == 0 {
+		t.Errorf("no small encodings decoded")
+	}
+}
+
+// The reference below follows RFC 9496 with a = -1, the Jubjub d and
+// SQRT_RATIO_M1 replaced by the zeta variant of sqrtRatio. It uses
+// math/big and the big.Int curve of the jubjub package, and derives its
+// constants at run time, so it shares no code with the implementation
+// beyond the BLAKE2b expansion of FromUniformBytes.
+// As in ristretto255, SQRT_AD_MINUS_ONE is the negative root; the other
+// constants are non-negative, and the sign of INVSQRT_A_MINUS_D does not
+// affect any output.
+
+type refGroup struct {
+	curve                                              *jubjub.Jubjub
+	q, d, zeta, sqrtM1, invSqrtAMinusD, sqrtADMinusOne *big.Int
+}
+
+func newRefGroup() *refGroup {
+	g := &refGroup{curve: jubjub.NewJubjub(), zeta: big.NewInt(7)}
+	g.q, g.d = g.curve.BlsR, g.curve.D
+
+	minusOne := g.mod(big.NewInt(-1))
+	g.sqrtM1 = g.abs(new(big.Int).ModSqrt(minusOne, g.q))
+	aMinusD := g.mod(new(big.Int).Sub(minusOne, g.d))
+	g.invSqrtAMinusD = g.abs(new(big.Int).ModInverse(new(big.Int).ModSqrt(aMinusD, g.q), g.q))
+	adMinusOne := g.mod(new(big.Int).Sub(new(big.Int).Neg(g.d), big.NewInt(1)))
+	g.sqrtADMinusOne = g.sub(big.NewInt(0), g.abs(new(big.Int).ModSqrt(adMinusOne, g.q)))
+	return g
+}
+
+func (g *refGroup) mod(x *big.Int) *big.Int {
+	return x.Mod(x, g.q)
+}
+
+func (g *refGroup) mul(xs ...*big.Int) *big.Int {
+	r := big.NewInt(1)
+	for _, x := range xs {
+		g.mod(r.Mul(r, x))
+	}
+	return r
+}
+
+func (g *refGroup) add(x, y *big.Int) *big.Int {
+	return g.mod(new(big.Int).Add(x, y))
+}
+
+func (g *refGroup) sub(x, y *big.Int) *big.Int {
+	return g.mod(new(big.Int).Sub(x, y))
+}
+
+func (g *refGroup) abs(x *big.Int) *big.Int {
+	if x.Bit(0) == 1 {
+		return g.sub(big.NewInt(0), x)
+	}
+	return x
+}
+
+func (g *refGroup) sqrtRatio(u, v *big.Int) (bool, *big.Int) {
+	if v.Sign() == 0 {
+		return u.Sign() == 0, big.NewInt(0)
+	}
+	w := g.mul(u, new(big.Int).ModInverse(v, g.q))
+	if r := new(big.Int).ModSqrt(w, g.q); r != nil {
+		return true, g.abs(r)
+	}
+	return false, g.abs(new(big.Int).ModSqrt(g.mul(g.zeta, w), g.q))
+}
+
+func (g *refGroup) decode(s *big.Int) (*jubjub.JubjubPoint, bool) {
+	if s.Cmp(g.q) >= 0 || s.Bit(0) == 1 {
+		return nil, false
+	}
+	one := big.NewInt(1)
+	ss := g.mul(s, s)
+	u1 := g.sub(one, ss)
+	u2 := g.add(one, ss)
+	u2Sqr := g.mul(u2, u2)
+	v := g.sub(g.sub(big.NewInt(0), g.mul(g.d, u1, u1)), u2Sqr)
+	wasSquare, invSqrt := g.sqrtRatio(one, g.mul(v, u2Sqr))
+	denX := g.mul(invSqrt, u2)
+	denY := g.mul(invSqrt, denX, v)
+	x := g.abs(g.mul(big.NewInt(2), s, denX))
+	y := g.mul(u1, denY)
+	if !wasSquare || g.mul(x, y).Bit(0) == 1 || y.Sign() == 0 {
+		return nil, false
+	}
+	p, err := g.curve.Point(x, y)
+	return p, err == nil
+}
+
+func (g *refGroup) encode(p *jubjub.JubjubPoint) *big.Int {
+	one := big.NewInt(1)
+	x0, y0 := p.X(), p.Y()
+	t0 := g.mul(x0, y0)
+	u1 := g.mul(g.add(one, y0), g.sub(one, y0))
+	u2 := g.mul(x0, y0)
+	_, invSqrt := g.sqrtRatio(one, g.mul(u1, u2, u2))
+	den1 := g.mul(invSqrt, u1)
+	den2 := g.mul(invSqrt, u2)
+	zInv := g.mul(den1, den2, t0)
+
+	x, y, denInv := x0, y0, den2
+	if g.mul(t0, zInv).Bit(0) == 1 {
+		x, y = g.mul(y0, g.sqrtM1), g.mul(x0, g.sqrtM1)
+		denInv = g.mul(den1, g.invSqrtAMinusD)
+	}
+	if g.mul(x, zInv).Bit(0) == 1 {
+		y = g.sub(big.NewInt(0), y)
+	}
+	return g.abs(g.mul(denInv, g.sub(one, y)))
+}
+
+func (g *refGroup) elligator(t *big.Int) *jubjub.JubjubPoint {
+	one := big.NewInt(1)
+	minusOne := g.sub(big.NewInt(0), one)
+	oneMinusDSq := g.sub(one, g.mul(g.d, g.d))
+	dMinusOneSq := g.mul(g.sub(g.d, one), g.sub(g.d, one))
+
+	r := g.mul(g.zeta, t, t)
+	u := g.mul(g.add(r, one), oneMinusDSq)
+	v := g.mul(g.sub(minusOne, g.mul(r, g.d)), g.add(r, g.d))
+	wasSquare, s := g.sqrtRatio(u, v)
+	c := minusOne
+	if !wasSquare {
+		s = g.sub(big.NewInt(0), g.abs(g.mul(s, t)))
+		c = r
+	}
+	n := g.sub(g.mul(c, g.sub(r, one), dMinusOneSq), v)
+	ss := g.mul(s, s)
+	w0 := g.mul(big.NewInt(2), s, v)
+	w1 := g.mul(n, g.sqrtADMinusOne)
+	w2 := g.sub(one, ss)
+	w3 := g.add(one, ss)
+
+	zInv := new(big.Int).ModInverse(g.mul(w1, w3), g.q)
+	p, err := g.curve.Point(g.mul(w0, w3, zInv), g.mul(w2, w1, zInv))
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func leHex(x *big.Int) string {
+	byt := make([]byte, 32)
+	x.FillBytes(byt)
+	futil.Reverse(byt)
+	return hex.EncodeToString(byt)
+}
+
+func TestReferenceEncoding(t *testing.T) {
+	g := newRefGroup()
+	refB, ok := g.decode(big.NewInt(10))
+	if !ok {
+		t.Fatal("reference decoding of s = 10 failed")
+	}
+	refT4, err := g.curve.Point(g.sqrtM1, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k, want := range multiplesOfB {
+		p, err := g.curve.ScalarMult(big.NewInt(int64(k)), refB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := leHex(g.encode(p)); got != want {
+			t.Errorf("reference encoding of [%d]B = %s, want %s", k, got, want)
+		}
+	}
+
+	b := mustDecode(t, multiplesOfB[1])
+	for i := 0; i < 8; i++ {
+		h := sha512.Sum512([]byte{byte(i)})
+		s, _ := fr.FromBytesWide(h[:])
+		sBytes := s.Bytes()
+		futil.Reverse(sBytes)
+		want, err := g.curve.ScalarMult(new(big.Int).SetBytes(sBytes), refB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// a 4-torsion component must not change the encoding
+		if i%2 == 1 {
+			if want, err = g.curve.Add(want, refT4); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := hex.EncodeToString(b.Mul(s).Bytes()); got != leHex(g.encode(want)) {
+			t.Errorf("[%s]B = %s, reference %s", s, got, leHex(g.encode(want)))
+		}
+	}
+
+	for _, v := range uniformVectors {
+		var ts [2]*big.Int
+		for i := range ts {
+			h, err := blake2b.New512WithPersonalization(nil, []byte("JubjubDecaf_Unif"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			h.Write(append([]byte{byte(i)}, uniformInput(v.label)...))
+			digest := h.Sum(nil)
+			futil.Reverse(digest)
+			ts[i] = g.mod(new(big.Int).SetBytes(digest))
+		}
+		p, err := g.curve.Add(g.elligator(ts[0]), g.elligator(ts[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := leHex(g.encode(p)); got != v.encoding {
+			t.Errorf("reference FromUniformBytes(%q) = %s, want %s", v.label, got, v.encoding)
+		}
+	}
+
+	// Decode accepts exactly the encodings the reference accepts
+	byt := make([]byte, 32)
+	for s := 0; s < 512; s++ {
+		byt[0], byt[1] = byte(s), byte(s>>8)
+		_, err := Decode(byt)
+		if _, ok := g.decode(big.NewInt(int64(s))); ok != (err == nil) {
+			t.Errorf("s = %d: Decode returned %v, reference accepts: %v", s, err, ok)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package jubjub
+
+import "errors"
+
+// Errors returned by the decoders and constructors of this module. The
+// subpackages re-export the ones they return, with the same values, so
+// they can be matched with errors.Is against either name.
+var (
+	ErrNotOnCurve         = errors.New("jubjub: point is not on the curve")
+	ErrNonCanonical       = errors.New("jubjub: non-canonical encoding")
+	ErrSmallOrder         = errors.New("jubjub: point of small order")
+	ErrNotInSubgroup      = errors.New("jubjub: point is not in the prime-order subgroup")
+	ErrInvalidLength      = errors.New("jubjub: invalid length")
+	ErrGroupHashExhausted = errors.New("jubjub: group hash found no valid point")
+	ErrInvalidWidth       = errors.New("jubjub: invalid window width")
+	ErrInvalidDepth       = errors.New("jubjub: invalid Merkle tree depth")
+)
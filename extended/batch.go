@@ -0,0 +1,121 @@
+package extended
+
+import (
+	"bytes"
+	"runtime"
+	"sync"
+
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/fq"
+)
+
+// Batches larger than this are split into chunks of this size, which
+// are decoded concurrently
+const batchChunkSize = 256
+
+// BatchFromBytes decodes a batch of 32 byte encodings with the same
+// checks as affine.FromBytes, sharing one field inversion per chunk of
+// the batch. errs[i] is nil if encodings[i] was decoded into points[i];
+// otherwise points[i] is nil and errs[i] is one of affine.ErrInvalidLength,
+// affine.ErrNonCanonical or affine.ErrNotOnCurve.
+func BatchFromBytes(encodings [][]byte) (points []*ExtendedPoint, errs []error) {
+	points = make([]*ExtendedPoint, len(encodings))
+	errs = make([]error, len(encodings))
+
+	if len(encodings) <= batchChunkSize {
+		batchFromBytes(encodings, points, errs)
+		return points, errs
+	}
+
+	chunks := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range chunks {
+				end := start + batchChunkSize
+				if end > len(encodings) {
+					end = len(encodings)
+				}
+				batchFromBytes(encodings[start:end], points[start:end], errs[start:end])
+			}
+		}()
+	}
+	for start := 0; start < len(encodings); start += batchChunkSize {
+		chunks <- start
+	}
+	close(chunks)
+	wg.Wait()
+
+	return points, errs
+}
+
+// batchFromBytes decodes encodings into points and errs, which have the
+// same length
+func batchFromBytes(encodings [][]byte, points []*ExtendedPoint, errs []error) {
+	vs := make([]*fq.Fq, len(encodings))
+	signs := make([]byte, len(encodings))
+	dens := make([]*fq.Fq, len(encodings))
+
+	for i, byt := range encodings {
+		dens[i] = fq.Zero()
+		if len(byt) != 32 {
+			errs[i] = affine.ErrInvalidLength
+			continue
+		}
+
+		tmp := make([]byte, 32)
+		copy(tmp, byt)
+		signs[i] = tmp[31] >> 7
+		tmp[31] &= 0b0111_1111
+
+		v, _ := fq.FromBytes(tmp)
+		if !bytes.Equal(v.Bytes(), tmp) {
+			errs[i] = affine.ErrNonCanonical
+			continue
+		}
+
+		// 1 + d.v^2 is never zero since -1/d is not a square
+		vs[i] = v
+		dens[i] = fq.One().Add(fq.D().Mul(v.Square()))
+	}
+
+	invs := fq.BatchInverse(dens)
+
+	for i, v := range vs {
+		if errs[i] != nil {
+			continue
+		}
+
+		u2 := v.Square().Sub(fq.One()).Mul(invs[i])
+		u := u2.Sqrt()
+		if !u.Square().Equal(u2) {
+			errs[i] = affine.ErrNotOnCurve
+			continue
+		}
+		if u.Equal(fq.Zero()) && signs[i] == 1 {
+			errs[i] = affine.ErrNonCanonical
+			continue
+		}
+
+		flip := (u.Bytes()[0] ^ signs[i]) & 1
+		points[i] = FromAffine(affine.FromRawUnchecked(fq.ConditionalSelect(u, u.Neg(), int(flip)), v))
+	}
+}
+
+// BatchToAffineNiels converts points to AffineNielsPoints, sharing one
+// field inversion across the batch
+func BatchToAffineNiels(points []*ExtendedPoint) []*AffineNielsPoint {
+	zs := make([]*fq.Fq, len(points))
+	for i, p := range points {
+		zs[i] = p.z
+	}
+	zinvs := fq.BatchInverse(zs)
+
+	niels := make([]*AffineNielsPoint, len(points))
+	for i, p := range points {
+		niels[i] = NielsFromAffine(affine.FromRawUnchecked(p.u.Mul(zinvs[i]), p.v.Mul(zinvs[i])))
+	}
+	return niels
+}
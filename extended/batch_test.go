@@ -0,0 +1,97 @@
+package extended
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/mechanizm/jubjub/affine"
+)
+
+func batchEncodings(n int) [][]byte {
+	p := BasePoint()
+	acc := Identity()
+
+	minusOne, _ := hex.DecodeString("00000000fffffffffe5bfeff02a4bd5305d8a10908d83933487d9d2953a7ed73")
+	q, _ := hex.DecodeString("01000000fffffffffe5bfeff02a4bd5305d8a10908d83933487d9d2953a7ed73")
+	minusZero, _ := hex.DecodeString("0100000000000000000000000000000000000000000000000000000000000080")
+	notOnCurve, _ := hex.DecodeString("0200000000000000000000000000000000000000000000000000000000000000")
+
+	encodings := make([][]byte, n)
+	for i := range encodings {
+		switch i % 7 {
+		case 1:
+			encodings[i] = minusOne
+		case 2:
+			encodings[i] = q
+		case 3:
+			encodings[i] = minusZero
+		case 4:
+			encodings[i] = notOnCurve
+		case 5:
+			encodings[i] = make([]byte, 31)
+		default:
+			encodings[i] = acc.Bytes()
+		}
+		acc = acc.Add(p)
+	}
+	return encodings
+}
+
+func TestBatchFromBytes(t *testing.T) {
+	for _, n := range []int{0, 1, 7, batchChunkSize, 3*batchChunkSize + 5} {
+		encodings := batchEncodings(n)
+		points, errs := BatchFromBytes(encodings)
+		if len(points) != n || len(errs) != n {
+			t.Fatalf("n = %d: got %d points and %d errors", n, len(points), len(errs))
+		}
+
+		for i, byt := range encodings {
+			want, wantErr := affine.FromBytes(byt)
+			if errs[i] != wantErr {
+				t.Errorf("n = %d: element %d: error %v, want %v", n, i, errs[i], wantErr)
+				continue
+			}
+			if wantErr != nil {
+				if points[i] != nil {
+					t.Errorf("n = %d: element %d: point returned with an error", n, i)
+				}
+				continue
+			}
+			if !points[i].Equal(FromAffine(want)) {
+				t.Errorf("n = %d: element %d: got %s, want %s", n, i, points[i], want)
+			}
+			if !bytes.Equal(points[i].Bytes(), byt) {
+				t.Errorf("n = %d: element %d does not round trip", n, i)
+			}
+		}
+	}
+}
+
+func TestBatchToAffineNiels(t *testing.T) {
+	p := BasePoint()
+	points := []*ExtendedPoint{Identity(), p, p.Double(), p.Double().Add(p).Neg()}
+	niels := BatchToAffineNiels(points)
+	for i, point := range points {
+		q := BasePoint()
+		if !q.AddAffineNiels(niels[i]).Equal(q.AddAffineNiels(point.ToAffineNiels())) {
+			t.Errorf("point %d: batch conversion differs from ToAffineNiels", i)
+		}
+	}
+}
+
+func BenchmarkBatchFromBytes(b *testing.B) {
+	encodings := batchEncodings(1024)
+	for i := 0; i < b.N; i++ {
+		BatchFromBytes(encodings)
+	}
+}
+
+func BenchmarkFromBytes(b *testing.B) {
+	encodings := batchEncodings(1024)
+	for i := 0; i < b.N; i++ {
+		for _, byt := range encodings {
+			affine.FromBytes(byt)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package extended
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/mechanizm/jubjub/fr"
+)
+
+// Run with -race: the precomputed tables are built lazily and shared
+func TestConcurrentUse(t *testing.T) {
+	A := testPoint(t)
+	a, b := randomScalar("a", 1), randomScalar("b", 1)
+	want := A.Mul(a).Add(BasePoint().Mul(b))
+
+	encodings := batchEncodings(2*batchChunkSize + 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := DoubleScalarMulVarTime(a, A, b); !got.Equal(want) {
+				t.Errorf("concurrent DoubleScalarMulVarTime gave %s, want %s", got, want)
+			}
+			if p, torsion := A.Decompose(); !p.Extended().Add(torsion).Equal(A) {
+				t.Errorf("concurrent Decompose does not reconstruct the point")
+			}
+			A.Neg().Add(A.Double()).Sub(A)
+			BatchFromBytes(encodings)
+		}()
+	}
+	wg.Wait()
+
+	if !A.Equal(testPoint(t)) {
+		t.Errorf("shared point was modified")
+	}
+	if *fr.One() != *fr.One().Mul(fr.One()) {
+		t.Errorf("fr.One changed")
+	}
+}
@@ -0,0 +1,95 @@
+package extended
+
+import (
+	"sync"
+
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/fq"
+	"github.com/mechanizm/jubjub/fr"
+)
+
+// basepoint is the Sapling spending key generator, the base point of
+// RedJubjub spend authorization signatures. Coordinates are in Montgomery
+// form. generators.SpendingKeyGenerator is the same point, which its
+// tests check; it cannot be used here because generators imports this
+// package.
+var basepoint = [2]fq.Fq{
+	{0x9fea675eb63e8cf6, 0x15ba8508eb7f13c5, 0x87a02da79c8b7ef8, 0x0af4897169c1851e},
+	{0xfb63146264e65a56, 0x77f3f8c6fd45d5e5, 0x8770a243986a6eb9, 0x6dde055ca112d037},
+}
+
+// Window widths of the non-adjacent forms. The table for B is computed
+// once and shared, so it can afford a wider window than the table for A.
+const (
+	varWindow   = 5
+	fixedWindow = 8
+)
+
+var (
+	basepointOnce  sync.Once
+	basepointTable []*AffineNielsPoint
+)
+
+// BasePoint returns the fixed base B used by DoubleScalarMulVarTime
+func BasePoint() *ExtendedPoint {
+	u, v := basepoint[0], basepoint[1]
+	return FromAffine(affine.FromRawUnchecked(&u, &v))
+}
+
+// DoubleScalarMulVarTime returns [a]A + [b]B, where B is BasePoint, as
+// needed to verify a signature.
+//
+// Its run time depends on a, A and b, so it must only be used with public
+// inputs.
+func DoubleScalarMulVarTime(a *fr.Fr, A *ExtendedPoint, b *fr.Fr) *ExtendedPoint {
+	// Both widths are valid constants, so ToWNAF cannot fail
+	aNaf, _ := a.ToWNAF(varWindow)
+	bNaf, _ := b.ToWNAF(fixedWindow)
+
+	oddA := oddMultiples(A, varWindow)
+	tableA := make([]*ExtendedNielsPoint, len(oddA))
+	for i, p := range oddA {
+		tableA[i] = p.ToNiels()
+	}
+	basepointOnce.Do(func() {
+		odd := oddMultiples(BasePoint(), fixedWindow)
+		basepointTable = make([]*AffineNielsPoint, len(odd))
+		for i, p := range odd {
+			basepointTable[i] = p.ToAffineNiels()
+		}
+	})
+
+	i := len(aNaf) - 1
+	for i >= 0 && aNaf[i] == 0 && bNaf[i] == 0 {
+		i--
+	}
+
+	acc := Identity()
+	for ; i >= 0; i-- {
+		acc = acc.Double()
+
+		if aNaf[i] > 0 {
+			acc = acc.AddExtendedNiels(tableA[aNaf[i]/2])
+		} else if aNaf[i] < 0 {
+			acc = acc.SubExtendedNiels(tableA[-aNaf[i]/2])
+		}
+
+		if bNaf[i] > 0 {
+			acc = acc.AddAffineNiels(basepointTable[bNaf[i]/2])
+		} else if bNaf[i] < 0 {
+			acc = acc.SubAffineNiels(basepointTable[-bNaf[i]/2])
+		}
+	}
+	return acc
+}
+
+// oddMultiples returns [P, [3]P, [5]P, ..., [2^(w-1) - 1]P]
+func oddMultiples(p *ExtendedPoint, w uint) []*ExtendedPoint {
+	table := make([]*ExtendedPoint, 1<<(w-2))
+	table[0] = p
+	p2 := p.Double().ToNiels()
+	for i := 1; i < len(table); i++ {
+		table[i] = table[i-1].AddExtendedNiels(p2)
+	}
+	return table
+}
@@ -0,0 +1,58 @@
+package extended
+
+import (
+	"testing"
+
+	"github.com/mechanizm/jubjub/fr"
+)
+
+func randomScalar(seed string, i int) *fr.Fr {
+	return hashScalar([]byte{seed[0], byte(i)})
+}
+
+func TestBasePointIsPrimeOrder(t *testing.T) {
+	if !BasePoint().IsPrimeOrder() {
+		t.Errorf("BasePoint is not in the prime-order subgroup")
+	}
+}
+
+func TestDoubleScalarMulVarTime(t *testing.T) {
+	A := testPoint(t)
+	B := BasePoint()
+
+	minusOne := fr.One().Neg()
+	scalars := [][2]*fr.Fr{
+		{fr.Zero(), fr.Zero()},
+		{fr.One(), fr.Zero()},
+		{fr.Zero(), fr.One()},
+		{minusOne, minusOne},
+	}
+	for i := 0; i < 16; i++ {
+		scalars = append(scalars, [2]*fr.Fr{randomScalar("a", i), randomScalar("b", i)})
+	}
+
+	for _, s := range scalars {
+		a, b := s[0], s[1]
+		want := A.Mul(a).Add(B.Mul(b))
+		if got := DoubleScalarMulVarTime(a, A, b); !got.Equal(want) {
+			t.Errorf("[%s]A + [%s]B = %s, want %s", a, b, got, want)
+		}
+	}
+}
+
+func BenchmarkDoubleScalarMulVarTime(b *testing.B) {
+	A := BasePoint().Double()
+	s1, s2 := randomScalar("a", 0), randomScalar("b", 0)
+	for i := 0; i < b.N; i++ {
+		DoubleScalarMulVarTime(s1, A, s2)
+	}
+}
+
+func BenchmarkTwoMuls(b *testing.B) {
+	A := BasePoint().Double()
+	B := BasePoint()
+	s1, s2 := randomScalar("a", 0), randomScalar("b", 0)
+	for i := 0; i < b.N; i++ {
+		A.Mul(s1).Add(B.Mul(s2))
+	}
+}
@@ -5,21 +5,41 @@ import (
 
 	"github.com/mechanizm/jubjub/affine"
 	"github.com/mechanizm/jubjub/fq"
+	"github.com/mechanizm/jubjub/fr"
 )
 
+// ExtendedPoint is a point in extended twisted Edwards coordinates. It is
+// immutable: every method returns a new point and leaves its arguments
+// untouched, so points can be shared between goroutines.
 type ExtendedPoint struct {
 	u, v, z, t1, t2 *fq.Fq
 }
 
-func FromBytes(byt []byte) *ExtendedPoint {
-	a := affine.FromBytesInner(byt)
-	return &ExtendedPoint{
-		u:  a.U,
-		v:  a.V,
-		z:  fq.One(),
-		t1: a.U,
-		t2: a.V,
+// FromBytes decodes a point with the checks of affine.FromBytes
+func FromBytes(byt []byte) (*ExtendedPoint, error) {
+	a, err := affine.FromBytes(byt)
+	if err != nil {
+		return nil, err
+	}
+	return FromAffine(a), nil
+}
+
+// FromBytesPreZIP216 decodes a point with the checks of
+// affine.FromBytesInner. Like the decoder of this package before it
+// returned errors, it accepts non-canonical encodings of v and of u = 0,
+// as the Sapling consensus rules did before ZIP 216. New code should use
+// FromBytes.
+func FromBytesPreZIP216(byt []byte) (*ExtendedPoint, error) {
+	a, err := affine.FromBytesInner(byt)
+	if err != nil {
+		return nil, err
 	}
+	return FromAffine(a), nil
+}
+
+// FromPointBytes decodes a point with the checks of affine.FromBytes
+func FromPointBytes(b affine.PointBytes) (*ExtendedPoint, error) {
+	return FromBytes(b[:])
 }
 
 func FromRawUnchecked(u, v *fq.Fq) *ExtendedPoint {
@@ -30,12 +50,34 @@ func (lhs *ExtendedPoint) Add(rhs *ExtendedPoint) *ExtendedPoint {
 	return lhs.AddExtendedNiels(rhs.ToNiels())
 }
 
-func (e *ExtendedPoint) Mul(buf []byte) *ExtendedPoint {
-	return e.ToNiels().Mul(buf)
+// Neg returns the negation of e, (-u, v)
+func (e *ExtendedPoint) Neg() *ExtendedPoint {
+	return &ExtendedPoint{
+		u:  e.u.Neg(),
+		v:  fq.Set(e.v),
+		z:  fq.Set(e.z),
+		t1: e.t1.Neg(),
+		t2: fq.Set(e.t2),
+	}
+}
+
+func (lhs *ExtendedPoint) Sub(rhs *ExtendedPoint) *ExtendedPoint {
+	return lhs.Add(rhs.Neg())
+}
+
+// Mul returns [s]e
+func (e *ExtendedPoint) Mul(s *fr.Fr) *ExtendedPoint {
+	return e.ToNiels().Mul(s)
+}
+
+// U returns the affine u-coordinate of e
+func (e *ExtendedPoint) U() *fq.Fq {
+	return e.u.Mul(e.z.Inverse())
 }
 
+// V returns the affine v-coordinate of e
 func (e *ExtendedPoint) V() *fq.Fq {
-	return e.v
+	return e.v.Mul(e.z.Inverse())
 }
 
 // mul_by_cofactor
@@ -80,12 +122,27 @@ func (e *ExtendedPoint) AddExtendedNiels(other *ExtendedNielsPoint) *ExtendedPoi
 	return point.Extended()
 }
 
+func (e *ExtendedPoint) SubExtendedNiels(other *ExtendedNielsPoint) *ExtendedPoint {
+	a := (e.v.Sub(e.u)).Mul(other.vPlusU)
+	b := (e.v.Add(e.u)).Mul(other.VminusU)
+	c := e.t1.Mul(e.t2).Mul(other.t2d)
+	d := (e.z.Mul(other.z)).Double()
+
+	point := &CompletedPoint{
+		u: b.Sub(a),
+		v: b.Add(a),
+		z: d.Sub(c),
+		t: d.Add(c),
+	}
+	return point.Extended()
+}
+
 func Identity() *ExtendedPoint {
 	return &ExtendedPoint{
-		u:  fq.One(),
+		u:  fq.Zero(),
 		v:  fq.One(),
 		z:  fq.One(),
-		t1: fq.One(),
+		t1: fq.Zero(),
 		t2: fq.One(),
 	}
 }
@@ -94,25 +151,33 @@ func (e *ExtendedPoint) IsIdentity() bool {
 	return e.u.Equal(fq.Zero()) && e.v.Equal(e.z)
 }
 
+// Equal returns true if e and o represent the same affine point
+func (e *ExtendedPoint) Equal(o *ExtendedPoint) bool {
+	return e.u.Mul(o.z).Equal(o.u.Mul(e.z)) && e.v.Mul(o.z).Equal(o.v.Mul(e.z))
+}
+
 func (e *ExtendedPoint) Bytes() []byte {
 	return e.ToAffine().Bytes()
 }
 
+// PointBytes returns the encoding of e
+func (e *ExtendedPoint) PointBytes() affine.PointBytes {
+	return e.ToAffine().PointBytes()
+}
+
 func (e *ExtendedPoint) ToAffine() *affine.AffinePoint {
 	zinv := e.z.Inverse()
-	return &affine.AffinePoint{
-		U: e.u.Mul(zinv),
-		V: e.v.Mul(zinv),
-	}
+	return affine.FromRawUnchecked(e.u.Mul(zinv), e.v.Mul(zinv))
 }
 
 func FromAffine(a *affine.AffinePoint) *ExtendedPoint {
+	u, v := a.U(), a.V()
 	return &ExtendedPoint{
-		u:  a.U,
-		v:  a.V,
+		u:  u,
+		v:  v,
 		z:  fq.One(),
-		t1: a.U,
-		t2: a.V,
+		t1: u,
+		t2: v,
 	}
 }
 
@@ -125,7 +190,7 @@ func (e *ExtendedPoint) ToNiels() *ExtendedNielsPoint {
 	en.VminusU = e.v.Sub(e.u)
 	en.vPlusU = e.v.Add(e.u)
 	en.z = fq.Set(e.z)
-	en.t2d = e.t1.Mul(e.t2).Mul(&fq.EDWARDS_D2)
+	en.t2d = e.t1.Mul(e.t2).Mul(fq.D2())
 	return &en
 }
 
@@ -134,25 +199,24 @@ func IdentityExtendedNielsPoint() *ExtendedNielsPoint {
 		vPlusU:  fq.One(),
 		VminusU: fq.One(),
 		z:       fq.One(),
-		t2d:     fq.One(),
+		t2d:     fq.Zero(),
 	}
 }
 
-func (niel *ExtendedNielsPoint) Mul(buf []byte) *ExtendedPoint {
+// Mul returns [s]P, where P is the point niel was computed from
+func (niel *ExtendedNielsPoint) Mul(s *fr.Fr) *ExtendedPoint {
 	zero := IdentityExtendedNielsPoint()
 	acc := Identity()
 
-	var bytes []int
-	for i := len(buf) - 1; i >= 0; i-- {
-		byt := buf[i]
-		for j := 7; j >= 0; j-- {
-			bytes = append(bytes, int((byt>>j)&1))
-		}
-	}
-
-	for _, bit := range bytes[4:] {
+	// s < r < 2^252, so the top 4 bits are zero
+	bits := s.Bits()
+	for i := 251; i >= 0; i-- {
 		acc = acc.Double()
 
+		bit := 0
+		if bits[i] {
+			bit = 1
+		}
 		acc = acc.AddExtendedNiels(ConditionalSelectExtendedNielsPoint(zero, niel, bit))
 	}
 	return acc
@@ -167,6 +231,95 @@ func ConditionalSelectExtendedNielsPoint(a, b *ExtendedNielsPoint, choice int) *
 	}
 }
 
+// AffineNielsPoint is a precomputed form (v+u, v-u, 2d.u.v) of an affine
+// point. Adding it to an ExtendedPoint saves the multiplication by Z
+// that an ExtendedNielsPoint needs, so it is the preferred form for
+// constant base points.
+type AffineNielsPoint struct {
+	vPlusU, vMinusU, t2d *fq.Fq
+}
+
+// NielsFromAffine precomputes the AffineNielsPoint of a
+func NielsFromAffine(a *affine.AffinePoint) *AffineNielsPoint {
+	u, v := a.U(), a.V()
+	return &AffineNielsPoint{
+		vPlusU:  v.Add(u),
+		vMinusU: v.Sub(u),
+		t2d:     u.Mul(v).Mul(fq.D2()),
+	}
+}
+
+// ToAffineNiels converts e to an AffineNielsPoint. It costs an inversion,
+// which pays off when the point is added many times.
+func (e *ExtendedPoint) ToAffineNiels() *AffineNielsPoint {
+	return NielsFromAffine(e.ToAffine())
+}
+
+func IdentityAffineNielsPoint() *AffineNielsPoint {
+	return &AffineNielsPoint{
+		vPlusU:  fq.One(),
+		vMinusU: fq.One(),
+		t2d:     fq.Zero(),
+	}
+}
+
+func (e *ExtendedPoint) AddAffineNiels(other *AffineNielsPoint) *ExtendedPoint {
+	a := (e.v.Sub(e.u)).Mul(other.vMinusU)
+	b := (e.v.Add(e.u)).Mul(other.vPlusU)
+	c := e.t1.Mul(e.t2).Mul(other.t2d)
+	d := e.z.Double()
+
+	point := &CompletedPoint{
+		u: b.Sub(a),
+		v: b.Add(a),
+		z: d.Add(c),
+		t: d.Sub(c),
+	}
+	return point.Extended()
+}
+
+func (e *ExtendedPoint) SubAffineNiels(other *AffineNielsPoint) *ExtendedPoint {
+	a := (e.v.Sub(e.u)).Mul(other.vPlusU)
+	b := (e.v.Add(e.u)).Mul(other.vMinusU)
+	c := e.t1.Mul(e.t2).Mul(other.t2d)
+	d := e.z.Double()
+
+	point := &CompletedPoint{
+		u: b.Sub(a),
+		v: b.Add(a),
+		z: d.Sub(c),
+		t: d.Add(c),
+	}
+	return point.Extended()
+}
+
+// Mul performs a fixed-base scalar multiplication by s
+func (niel *AffineNielsPoint) Mul(s *fr.Fr) *ExtendedPoint {
+	zero := IdentityAffineNielsPoint()
+	acc := Identity()
+
+	// s < r < 2^252, so the top 4 bits are zero
+	bits := s.Bits()
+	for i := 251; i >= 0; i-- {
+		acc = acc.Double()
+
+		bit := 0
+		if bits[i] {
+			bit = 1
+		}
+		acc = acc.AddAffineNiels(ConditionalSelectAffineNielsPoint(zero, niel, bit))
+	}
+	return acc
+}
+
+func ConditionalSelectAffineNielsPoint(a, b *AffineNielsPoint, choice int) *AffineNielsPoint {
+	return &AffineNielsPoint{
+		vPlusU:  fq.ConditionalSelect(a.vPlusU, b.vPlusU, choice),
+		vMinusU: fq.ConditionalSelect(a.vMinusU, b.vMinusU, choice),
+		t2d:     fq.ConditionalSelect(a.t2d, b.t2d, choice),
+	}
+}
+
 func (e *ExtendedPoint) String() string {
 	return fmt.Sprintf("u: %s, v: %s, z: %s, t1: %s, t2: %s", e.u.String(), e.v.String(), e.z.String(), e.t1.String(), e.t2.String())
 }
@@ -0,0 +1,77 @@
+package extended
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"testing"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/fr"
+)
+
+func testPoint(t *testing.T) *ExtendedPoint {
+	byt, err := hex.DecodeString("7d09bb9aa97704719c33d1f6e7ed7e8d6c0edad0a02f7af82ab77ebc104f5f1e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := FromBytes(byt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// hashScalar derives a scalar from msg
+func hashScalar(msg []byte) *fr.Fr {
+	h := sha512.Sum512(msg)
+	s, _ := fr.FromBytesWide(h[:])
+	return s
+}
+
+func TestFromBytesPreZIP216(t *testing.T) {
+	// v = q + 1 is a non-canonical encoding of v = 1, the identity
+	qPlusOne, _ := hex.DecodeString("02000000fffffffffe5bfeff02a4bd5305d8a10908d83933487d9d2953a7ed73")
+	if _, err := FromBytes(qPlusOne); err != jubjub.ErrNonCanonical {
+		t.Errorf("FromBytes: expected ErrNonCanonical, got %v", err)
+	}
+	p, err := FromBytesPreZIP216(qPlusOne)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.IsIdentity() {
+		t.Errorf("FromBytesPreZIP216(q + 1) = %s, want the identity", p)
+	}
+}
+
+func TestAffineNiels(t *testing.T) {
+	p := testPoint(t)
+	q := p.Double().Add(p)
+	niels := q.ToAffineNiels()
+
+	if got, want := p.AddAffineNiels(niels).Bytes(), p.Add(q).Bytes(); !bytes.Equal(got, want) {
+		t.Errorf("AddAffineNiels = %x, want %x", got, want)
+	}
+	if got, want := p.SubAffineNiels(niels).Bytes(), p.Sub(q).Bytes(); !bytes.Equal(got, want) {
+		t.Errorf("SubAffineNiels = %x, want %x", got, want)
+	}
+	if !p.AddAffineNiels(niels).SubAffineNiels(niels).Sub(p).IsIdentity() {
+		t.Errorf("P + Q - Q != P")
+	}
+	if got := Identity().AddAffineNiels(IdentityAffineNielsPoint()); !got.IsIdentity() {
+		t.Errorf("identity + identity = %s", got)
+	}
+}
+
+func TestAffineNielsMul(t *testing.T) {
+	p := testPoint(t)
+	niels := p.ToAffineNiels()
+
+	for i := 0; i < 8; i++ {
+		s := hashScalar([]byte{byte(i)})
+
+		if got, want := niels.Mul(s).Bytes(), p.Mul(s).Bytes(); !bytes.Equal(got, want) {
+			t.Errorf("AffineNielsPoint.Mul = %x, want %x", got, want)
+		}
+	}
+}
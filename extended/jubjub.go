@@ -0,0 +1,42 @@
+package extended
+
+import (
+	"math/big"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/fq"
+	"github.com/mechanizm/jubjub/futil"
+)
+
+// FromJubjubPoint converts a point of the big.Int backend
+func FromJubjubPoint(p *jubjub.JubjubPoint) *ExtendedPoint {
+	return FromRawUnchecked(bigToFq(p.X()), bigToFq(p.Y()))
+}
+
+// ToJubjubPoint converts e to a point of the big.Int backend
+func (e *ExtendedPoint) ToJubjubPoint(curve *jubjub.Jubjub) (*jubjub.JubjubPoint, error) {
+	a := e.ToAffine()
+	return curve.Point(fqToBig(a.U()), fqToBig(a.V()))
+}
+
+// AffineNielsFromJubjubPoint precomputes the AffineNielsPoint of a
+// point of the big.Int backend
+func AffineNielsFromJubjubPoint(p *jubjub.JubjubPoint) *AffineNielsPoint {
+	return NielsFromAffine(affine.FromRawUnchecked(bigToFq(p.X()), bigToFq(p.Y())))
+}
+
+func bigToFq(n *big.Int) *fq.Fq {
+	byt := make([]byte, 32)
+	n.FillBytes(byt)
+	futil.Reverse(byt)
+	// byt has the right length, so FromBytes cannot fail
+	f, _ := fq.FromBytes(byt)
+	return f
+}
+
+func fqToBig(f *fq.Fq) *big.Int {
+	byt := f.Bytes()
+	futil.Reverse(byt)
+	return new(big.Int).SetBytes(byt)
+}
@@ -0,0 +1,76 @@
+package extended
+
+import (
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/fr"
+)
+
+var ErrInvalidLength = jubjub.ErrInvalidLength
+
+// MultiScalarMul returns the sum of [scalars[i]]points[i] using Straus'
+// method: each point gets a table of its multiples 1..8 and every
+// radix-16 digit of its scalar selects one entry, so that all the points
+// share the 252 doublings. Table lookups scan the whole table and select
+// with the masked fq.ConditionalSelect, so the sequence of operations and
+// memory accesses does not depend on the scalars.
+//
+// It returns ErrInvalidLength if scalars and points have different
+// lengths.
+func MultiScalarMul(scalars []*fr.Fr, points []*ExtendedPoint) (*ExtendedPoint, error) {
+	if len(scalars) != len(points) {
+		return nil, ErrInvalidLength
+	}
+
+	digits := make([][64]int8, len(scalars))
+	tables := make([][8]*ExtendedNielsPoint, len(points))
+	for i := range points {
+		digits[i] = scalars[i].ToRadix16Signed()
+
+		p := points[i]
+		acc := p
+		tables[i][0] = p.ToNiels()
+		for k := 1; k < 8; k++ {
+			acc = acc.AddExtendedNiels(tables[i][0])
+			tables[i][k] = acc.ToNiels()
+		}
+	}
+
+	acc := Identity()
+	for j := 63; j >= 0; j-- {
+		if j != 63 {
+			acc = acc.Double().Double().Double().Double()
+		}
+		for i := range tables {
+			acc = acc.AddExtendedNiels(lookupSigned(&tables[i], digits[i][j]))
+		}
+	}
+	return acc, nil
+}
+
+// lookupSigned returns [d]P from the table of the multiples 1..8 of P,
+// for d in [-8, 8]
+func lookupSigned(table *[8]*ExtendedNielsPoint, d int8) *ExtendedNielsPoint {
+	// negative is 1 if d < 0, and abs is |d|
+	negative := int(uint8(d) >> 7)
+	mask := d >> 7
+	abs := uint8((d ^ mask) - mask)
+
+	res := IdentityExtendedNielsPoint()
+	for k := range table {
+		// choice is 1 if abs == k+1
+		choice := int((uint32(abs^uint8(k+1)) - 1) >> 31)
+		res = ConditionalSelectExtendedNielsPoint(res, table[k], choice)
+	}
+	return ConditionalSelectExtendedNielsPoint(res, res.neg(), negative)
+}
+
+// neg returns the ExtendedNielsPoint of -P. Negating u swaps v+u and
+// v-u, and negates 2d.t.
+func (niel *ExtendedNielsPoint) neg() *ExtendedNielsPoint {
+	return &ExtendedNielsPoint{
+		vPlusU:  niel.VminusU,
+		VminusU: niel.vPlusU,
+		z:       niel.z,
+		t2d:     niel.t2d.Neg(),
+	}
+}
@@ -0,0 +1,61 @@
+package extended
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mechanizm/jubjub/fr"
+)
+
+func TestMultiScalarMul(t *testing.T) {
+	var scalars []*fr.Fr
+	var points []*ExtendedPoint
+	want := Identity()
+	for n := 0; n < 20; n++ {
+		got, err := MultiScalarMul(scalars, points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("%d points: got %s, want %s", n, got, want)
+		}
+
+		s := randomScalar("msm scalar", n)
+		if n%5 == 0 {
+			s = s.Neg()
+		}
+		if n == 3 {
+			s = fr.Zero()
+		}
+		p := BasePoint().Mul(randomScalar("msm point", n))
+		if n == 7 {
+			// a point with a torsion component
+			p = p.Add(EightTorsion()[3])
+		}
+		scalars = append(scalars, s)
+		points = append(points, p)
+		want = want.Add(p.Mul(s))
+	}
+}
+
+func TestMultiScalarMulLengthMismatch(t *testing.T) {
+	if _, err := MultiScalarMul([]*fr.Fr{fr.One()}, nil); err != ErrInvalidLength {
+		t.Errorf("expected ErrInvalidLength, got %v", err)
+	}
+}
+
+func BenchmarkMultiScalarMul(b *testing.B) {
+	for _, n := range []int{1, 16, 256} {
+		scalars := make([]*fr.Fr, n)
+		points := make([]*ExtendedPoint, n)
+		for i := range scalars {
+			scalars[i] = hashScalar([]byte(fmt.Sprintf("scalar %d", i)))
+			points[i] = BasePoint().Mul(hashScalar([]byte(fmt.Sprintf("point %d", i))))
+		}
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				MultiScalarMul(scalars, points)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package extended
+
+import (
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/fq"
+)
+
+// The functions below follow the naming of the Sapling protocol
+// specification, sections 5.4.9.3 and 5.4.9.4.
+
+// Extract returns Extract_J(e), the u-coordinate of e. It is the value of
+// a note commitment cmu and of the leaves of the note commitment tree, and
+// is injective on the prime-order subgroup.
+func Extract(e *ExtendedPoint) *fq.Fq {
+	return e.U()
+}
+
+// Repr returns repr_J(e), the 32 byte encoding of e
+func Repr(e *ExtendedPoint) []byte {
+	return e.Bytes()
+}
+
+// Abst returns abst_J(byt), the point encoded by byt. Encodings that
+// are not canonical or not on the curve are rejected, as abst_J returns ⊥
+// for them.
+func Abst(byt []byte) (*ExtendedPoint, error) {
+	a, err := affine.FromBytes(byt)
+	if err != nil {
+		return nil, err
+	}
+	return FromAffine(a), nil
+}
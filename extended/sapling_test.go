@@ -0,0 +1,50 @@
+package extended
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/fq"
+	"github.com/mechanizm/jubjub/fr"
+)
+
+func TestExtract(t *testing.T) {
+	p := BasePoint().Mul(fr.One().Double().Double())
+	a := p.ToAffine()
+
+	if !Extract(p).Equal(a.U()) {
+		t.Errorf("Extract(P) = %s, want %s", Extract(p), a.U())
+	}
+	if !p.V().Equal(a.V()) {
+		t.Errorf("V() = %s, want %s", p.V(), a.V())
+	}
+
+	// P and -P have opposite u-coordinates
+	if !Extract(p.Neg()).Equal(a.U().Neg()) {
+		t.Errorf("Extract(-P) != -Extract(P)")
+	}
+	if !Extract(Identity()).Equal(fq.Zero()) {
+		t.Errorf("Extract(identity) = %s, want 0", Extract(Identity()))
+	}
+}
+
+func TestReprAbst(t *testing.T) {
+	p := BasePoint().Double()
+	byt := Repr(p)
+
+	q, err := Abst(byt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.Equal(p) || !bytes.Equal(Repr(q), byt) {
+		t.Errorf("abst_J(repr_J(P)) != P")
+	}
+
+	// u = -0 is not a canonical encoding of the identity
+	minusZero := make([]byte, 32)
+	minusZero[0], minusZero[31] = 1, 0x80
+	if _, err := Abst(minusZero); err != affine.ErrNonCanonical {
+		t.Errorf("expected ErrNonCanonical, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package extended
+
+import (
+	"fmt"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/fr"
+)
+
+var ErrNotInSubgroup = jubjub.ErrNotInSubgroup
+
+// SubgroupPoint is a point in the prime-order subgroup of Jubjub. It can
+// only be obtained through a checked conversion, and its arithmetic never
+// leaves the subgroup.
+type SubgroupPoint struct {
+	p *ExtendedPoint
+}
+
+// SubgroupIdentity returns the identity of the subgroup
+func SubgroupIdentity() *SubgroupPoint {
+	return &SubgroupPoint{p: Identity()}
+}
+
+// IsSmallOrder returns true if e is in the 8-torsion subgroup
+func (e *ExtendedPoint) IsSmallOrder() bool {
+	return e.MulByCofactor().IsIdentity()
+}
+
+// IsTorsionFree returns true if e is in the prime-order subgroup,
+// that is if [r]e is the identity. [r]e is computed as [r-1]e + e, since
+// r itself is not a canonical scalar.
+func (e *ExtendedPoint) IsTorsionFree() bool {
+	return e.Mul(fr.One().Neg()).Add(e).IsIdentity()
+}
+
+// IsPrimeOrder returns true if e is in the prime-order
+// subgroup and is not the identity
+func (e *ExtendedPoint) IsPrimeOrder() bool {
+	return e.IsTorsionFree() && !e.IsIdentity()
+}
+
+// ClearCofactor maps e into the prime-order subgroup by computing [8]e
+func (e *ExtendedPoint) ClearCofactor() *SubgroupPoint {
+	return &SubgroupPoint{p: e.MulByCofactor()}
+}
+
+// SubgroupPointFromExtended returns e as a SubgroupPoint if it has no
+// torsion component, and ErrNotInSubgroup otherwise
+func SubgroupPointFromExtended(e *ExtendedPoint) (*SubgroupPoint, error) {
+	if !e.IsTorsionFree() {
+		return nil, ErrNotInSubgroup
+	}
+	return &SubgroupPoint{p: e}, nil
+}
+
+// SubgroupPointFromBytes decodes a point and checks that it is in the
+// prime-order subgroup. Encodings that are not canonical, not on the
+// curve or that have a torsion component are rejected.
+func SubgroupPointFromBytes(byt []byte) (*SubgroupPoint, error) {
+	a, err := affine.FromBytes(byt)
+	if err != nil {
+		return nil, err
+	}
+	return SubgroupPointFromExtended(FromAffine(a))
+}
+
+// SubgroupPointFromPointBytes decodes a point with the checks of
+// SubgroupPointFromBytes
+func SubgroupPointFromPointBytes(b affine.PointBytes) (*SubgroupPoint, error) {
+	return SubgroupPointFromBytes(b[:])
+}
+
+// Extended returns s as a point on the full curve
+func (s *SubgroupPoint) Extended() *ExtendedPoint {
+	return s.p
+}
+
+func (s *SubgroupPoint) Add(o *SubgroupPoint) *SubgroupPoint {
+	return &SubgroupPoint{p: s.p.Add(o.p)}
+}
+
+func (s *SubgroupPoint) Sub(o *SubgroupPoint) *SubgroupPoint {
+	return &SubgroupPoint{p: s.p.Sub(o.p)}
+}
+
+func (s *SubgroupPoint) Neg() *SubgroupPoint {
+	return &SubgroupPoint{p: s.p.Neg()}
+}
+
+func (s *SubgroupPoint) Double() *SubgroupPoint {
+	return &SubgroupPoint{p: s.p.Double()}
+}
+
+// Mul returns [scalar]s
+func (s *SubgroupPoint) Mul(scalar *fr.Fr) *SubgroupPoint {
+	return &SubgroupPoint{p: s.p.Mul(scalar)}
+}
+
+func (s *SubgroupPoint) Equal(o *SubgroupPoint) bool {
+	return s.p.Equal(o.p)
+}
+
+func (s *SubgroupPoint) IsIdentity() bool {
+	return s.p.IsIdentity()
+}
+
+func (s *SubgroupPoint) Bytes() []byte {
+	return s.p.Bytes()
+}
+
+// PointBytes returns the encoding of s
+func (s *SubgroupPoint) PointBytes() affine.PointBytes {
+	return s.p.PointBytes()
+}
+
+func (s *SubgroupPoint) String() string {
+	return fmt.Sprintf("%x", s.Bytes())
+}
@@ -0,0 +1,106 @@
+package extended
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/fq"
+)
+
+func TestClearCofactor(t *testing.T) {
+	p := testPoint(t)
+	t2 := FromRawUnchecked(fq.Zero(), fq.One().Neg())
+
+	s := p.Add(t2).ClearCofactor()
+	if !s.Extended().IsTorsionFree() {
+		t.Fatalf("[8]P is not torsion free")
+	}
+	if !s.Equal(p.ClearCofactor()) {
+		t.Errorf("clearing the cofactor did not remove the torsion component")
+	}
+
+	if _, err := SubgroupPointFromExtended(s.Extended().Add(t2)); err != ErrNotInSubgroup {
+		t.Errorf("expected ErrNotInSubgroup, got %v", err)
+	}
+	if !t2.IsSmallOrder() || t2.IsTorsionFree() {
+		t.Errorf("(0, -1) is not classified as small order")
+	}
+}
+
+func TestSubgroupArithmetic(t *testing.T) {
+	s := testPoint(t).ClearCofactor()
+
+	k := hashScalar([]byte("scalar"))
+
+	results := []*SubgroupPoint{
+		s.Add(s.Double()),
+		s.Sub(s.Double()),
+		s.Neg(),
+		s.Mul(k),
+		SubgroupIdentity(),
+	}
+	for _, r := range results {
+		if !r.Extended().IsTorsionFree() {
+			t.Errorf("%s left the subgroup", r)
+		}
+	}
+
+	if !s.Add(s).Sub(s.Double()).IsIdentity() {
+		t.Errorf("S + S - [2]S is not the identity")
+	}
+	if !s.Mul(k).Add(s.Mul(k.Neg())).IsIdentity() {
+		t.Errorf("[k]S + [-k]S is not the identity")
+	}
+}
+
+func TestSubgroupPointFromBytes(t *testing.T) {
+	s := testPoint(t).ClearCofactor()
+
+	decoded, err := SubgroupPointFromBytes(s.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(s) {
+		t.Errorf("round trip failed")
+	}
+
+	// the same point with a component of order 2
+	withTorsion := s.Extended().Add(FromRawUnchecked(fq.Zero(), fq.One().Neg()))
+	if _, err := SubgroupPointFromBytes(withTorsion.Bytes()); err != ErrNotInSubgroup {
+		t.Errorf("expected ErrNotInSubgroup, got %v", err)
+	}
+
+	// v = q is not canonical
+	q, _ := hex.DecodeString("01000000fffffffffe5bfeff02a4bd5305d8a10908d83933487d9d2953a7ed73")
+	// v = 2 is not on the curve
+	two := make([]byte, 32)
+	two[0] = 2
+	// the identity with the sign bit set encodes u = -0
+	minusZero := make([]byte, 32)
+	minusZero[0], minusZero[31] = 1, 0x80
+
+	vectors := []struct {
+		byt []byte
+		err error
+	}{
+		{q, affine.ErrNonCanonical},
+		{two, affine.ErrNotOnCurve},
+		{minusZero, affine.ErrNonCanonical},
+		{make([]byte, 31), affine.ErrInvalidLength},
+	}
+	for _, v := range vectors {
+		if _, err := SubgroupPointFromBytes(v.byt); err != v.err {
+			t.Errorf("SubgroupPointFromBytes(%x) = %v, want %v", v.byt, err, v.err)
+		}
+	}
+
+	// decoding does not modify its input
+	byt := withTorsion.Bytes()
+	orig := append([]byte{}, byt...)
+	SubgroupPointFromBytes(byt)
+	if !bytes.Equal(byt, orig) {
+		t.Errorf("SubgroupPointFromBytes modified its input")
+	}
+}
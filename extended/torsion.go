@@ -0,0 +1,81 @@
+package extended
+
+import (
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/fq"
+	"github.com/mechanizm/jubjub/fr"
+)
+
+// eightTorsion[k] holds the (u, v) coordinates of [k]T in Montgomery form,
+// where T = eightTorsion[1] generates the 8-torsion subgroup
+var eightTorsion = [8][2]fq.Fq{
+	{
+		{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+		{0x00000001fffffffe, 0x5884b7fa00034802, 0x998c4fefecbc4ff5, 0x1824b159acc5056f},
+	},
+	{
+		{0xad54905676840a17, 0x884a28f1a8cee9b4, 0xdfcc6227f79d2e0c, 0x45784f13df4a06a9},
+		{0xdc2e8792ad17413b, 0x22a13f6d0d805e26, 0x87876d4df48e7492, 0x3e344d8cbceee813},
+	},
+	{
+		{0x0c4fa98a55763050, 0x4c8ea2c29ff7a200, 0x649fca48e43b5ddf, 0x26c0c34dfc43f9d3},
+		{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+	},
+	{
+		{0xad54905676840a17, 0x884a28f1a8cee9b4, 0xdfcc6227f79d2e0c, 0x45784f13df4a06a9},
+		{0x23d1786c52e8bec6, 0x311c6495f27dfdd8, 0xabb26aba15136373, 0x35b959c66cae9534},
+	},
+	{
+		{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+		{0xfffffffd00000003, 0xfb38ec08fffb13fc, 0x99ad88181ce5880f, 0x5bc8f5f97cd877d8},
+	},
+	{
+		{0x52ab6fa8897bf5ea, 0xcb737b11572f724a, 0x536d75e01204a9f8, 0x2e75583f4a53769e},
+		{0x23d1786c52e8bec6, 0x311c6495f27dfdd8, 0xabb26aba15136373, 0x35b959c66cae9534},
+	},
+	{
+		{0xf3b05674aa89cfb1, 0x072f01406006b9fe, 0xce9a0dbf25667a26, 0x4d2ce4052d598374},
+		{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+	},
+	{
+		{0x52ab6fa8897bf5ea, 0xcb737b11572f724a, 0x536d75e01204a9f8, 0x2e75583f4a53769e},
+		{0xdc2e8792ad17413b, 0x22a13f6d0d805e26, 0x87876d4df48e7492, 0x3e344d8cbceee813},
+	},
+}
+
+// inv8 = 8^-1 mod r in Montgomery form
+var inv8 = fr.Fr{0x5ed1e3425211a692, 0xb32fbed8666fdefa, 0xf33189fdfd9789fe, 0x0304962b3598a0ad}
+
+// EightTorsion returns the eight points of small order. The point at
+// index k is [k]T, where T at index 1 generates the 8-torsion subgroup:
+// index 0 is the identity, 4 is (0, -1) of order 2, 2 and 6 have order 4
+// and the odd indices have order 8.
+func EightTorsion() [8]*ExtendedPoint {
+	var points [8]*ExtendedPoint
+	for k := range eightTorsion {
+		u, v := eightTorsion[k][0], eightTorsion[k][1]
+		points[k] = FromAffine(affine.FromRawUnchecked(&u, &v))
+	}
+	return points
+}
+
+// Decompose splits e into its prime-order component p and its torsion
+// component t, so that e = p + t
+func (e *ExtendedPoint) Decompose() (*SubgroupPoint, *ExtendedPoint) {
+	// [8^-1 mod r]([8]e) keeps the prime-order component and kills the
+	// torsion component
+	p := e.MulByCofactor().Mul(&inv8)
+	return &SubgroupPoint{p: p}, e.Sub(p)
+}
+
+// TorsionIndex returns k such that the torsion component of e is [k]T,
+// with T as in EightTorsion. It returns -1 if e is not on the curve.
+func (e *ExtendedPoint) TorsionIndex() int {
+	_, t := e.Decompose()
+	for k, tk := range EightTorsion() {
+		if t.Equal(tk) {
+			return k
+		}
+	}
+	return -1
+}
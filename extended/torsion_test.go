@@ -0,0 +1,94 @@
+package extended
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/fr"
+)
+
+// Canonical encodings of [k]T
+var eightTorsionEncodings = []string{
+	"0100000000000000000000000000000000000000000000000000000000000000",
+	"dd96f4ef68200dffa1a484f390ee069166724dad3530a1162e986619b2bd58c9",
+	"0000000000000000000000000000000000000000000000000000000000000080",
+	"24690b1096dff2005db7790c72b5b6c29e65545cd2a7981c1ae53610a1e994aa",
+	"00000000fffffffffe5bfeff02a4bd5305d8a10908d83933487d9d2953a7ed73",
+	"24690b1096dff2005db7790c72b5b6c29e65545cd2a7981c1ae53610a1e9942a",
+	"0000000000000000000000000000000000000000000000000000000000000000",
+	"dd96f4ef68200dffa1a484f390ee069166724dad3530a1162e986619b2bd5849",
+}
+
+func TestEightTorsionEncodings(t *testing.T) {
+	points := EightTorsion()
+	for k, enc := range eightTorsionEncodings {
+		byt, err := hex.DecodeString(enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, err := affine.FromBytes(byt)
+		if err != nil {
+			t.Fatalf("[%d]T: %v", k, err)
+		}
+		if !FromAffine(a).Equal(points[k]) {
+			t.Errorf("decoding of [%d]T does not match the constant", k)
+		}
+		if got := hex.EncodeToString(points[k].Bytes()); got != enc {
+			t.Errorf("[%d]T encodes to %s, want %s", k, got, enc)
+		}
+	}
+}
+
+func TestEightTorsionOrders(t *testing.T) {
+	points := EightTorsion()
+	orders := []int{1, 8, 4, 8, 2, 8, 4, 8}
+
+	for k, p := range points {
+		if !p.IsSmallOrder() {
+			t.Errorf("[%d]T is not of small order", k)
+		}
+		acc := p
+		order := 1
+		for !acc.IsIdentity() {
+			acc = acc.Add(p)
+			order++
+		}
+		if order != orders[k] {
+			t.Errorf("[%d]T has order %d, want %d", k, order, orders[k])
+		}
+		buf := make([]byte, 32)
+		buf[0] = byte(k)
+		scalar, err := fr.FromBytes(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !points[1].Mul(scalar).Equal(p) {
+			t.Errorf("point at index %d is not [%d]T", k, k)
+		}
+	}
+}
+
+func TestDecompose(t *testing.T) {
+	base := testPoint(t).ClearCofactor()
+	points := EightTorsion()
+
+	for k, tk := range points {
+		s := base.Mul(hashScalar([]byte{byte(k)}))
+		p := s.Extended().Add(tk)
+
+		prime, torsion := p.Decompose()
+		if !prime.Equal(s) {
+			t.Errorf("prime-order component of S + [%d]T is not S", k)
+		}
+		if !torsion.Equal(tk) {
+			t.Errorf("torsion component of S + [%d]T is not [%d]T", k, k)
+		}
+		if !prime.Extended().Add(torsion).Equal(p) {
+			t.Errorf("decomposition of S + [%d]T does not reconstruct it", k)
+		}
+		if got := p.TorsionIndex(); got != k {
+			t.Errorf("TorsionIndex(S + [%d]T) = %d", k, got)
+		}
+	}
+}
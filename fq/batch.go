@@ -0,0 +1,28 @@
+package fq
+
+// BatchInverse inverts every element of fs with a single field inversion
+// using Montgomery's trick. Zero elements are mapped to zero.
+func BatchInverse(fs []*Fq) []*Fq {
+	// acc[i] is the product of the non-zero elements of fs[:i]
+	acc := make([]*Fq, len(fs)+1)
+	acc[0] = One()
+	for i, f := range fs {
+		acc[i+1] = acc[i]
+		if !f.Equal(Zero()) {
+			acc[i+1] = acc[i].Mul(f)
+		}
+	}
+
+	inv := acc[len(fs)].Inverse()
+
+	res := make([]*Fq, len(fs))
+	for i := len(fs) - 1; i >= 0; i-- {
+		if fs[i].Equal(Zero()) {
+			res[i] = Zero()
+			continue
+		}
+		res[i] = inv.Mul(acc[i])
+		inv = inv.Mul(fs[i])
+	}
+	return res
+}
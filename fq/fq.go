@@ -3,14 +3,25 @@ package fq
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 
+	"github.com/mechanizm/jubjub"
 	"github.com/mechanizm/jubjub/futil"
 )
 
 type Fq [4]uint64
 
-// from_bytes
-func FromBytes(byt []byte) *Fq {
+var (
+	ErrInvalidLength = jubjub.ErrInvalidLength
+	ErrNonSquare     = errors.New("fq: element is not a square")
+)
+
+// FromBytes reduces a 32 byte little-endian integer modulo q
+func FromBytes(byt []byte) (*Fq, error) {
+	if len(byt) != 32 {
+		return nil, ErrInvalidLength
+	}
+
 	d := &Fq{0, 0, 0, 0}
 
 	d[0] = binary.LittleEndian.Uint64(byt[0:8])
@@ -19,11 +30,37 @@ func FromBytes(byt []byte) *Fq {
 	d[3] = binary.LittleEndian.Uint64(byt[24:32])
 
 	// Convert to Montgomery form
-	return d.Mul(&R2)
+	return d.Mul(&montR2), nil
+}
+
+// FromBytesWide reduces a 64 byte little-endian integer modulo q
+func FromBytesWide(byt []byte) (*Fq, error) {
+	if len(byt) != 64 {
+		return nil, ErrInvalidLength
+	}
+
+	d0 := &Fq{0, 0, 0, 0}
+	d1 := &Fq{0, 0, 0, 0}
+
+	d0[0] = binary.LittleEndian.Uint64(byt[0:8])
+	d0[1] = binary.LittleEndian.Uint64(byt[8:16])
+	d0[2] = binary.LittleEndian.Uint64(byt[16:24])
+	d0[3] = binary.LittleEndian.Uint64(byt[24:32])
+
+	d1[0] = binary.LittleEndian.Uint64(byt[32:40])
+	d1[1] = binary.LittleEndian.Uint64(byt[40:48])
+	d1[2] = binary.LittleEndian.Uint64(byt[48:56])
+	d1[3] = binary.LittleEndian.Uint64(byt[56:64])
+
+	// Convert to Montgomery form
+	d0 = d0.Mul(&montR2)
+	d1 = d1.Mul(&montR3)
+
+	return d0.Add(d1), nil
 }
 
 func FromRaw(f *Fq) *Fq {
-	return f.Mul(&R2)
+	return f.Mul(&montR2)
 }
 
 // Sub Subtracts one field from another
@@ -57,15 +94,11 @@ func (a *Fq) Neg() *Fq {
 	d2, borrow := futil.Sbb(q[2], a[2], borrow)
 	d3, _ := futil.Sbb(q[3], a[3], borrow)
 
-	msk := a[0]|a[1]|a[2]|a[3] == 0
-
-	var mask uint64
-	if !msk {
-		mask-- // uint64 max
-	}
-
 	// `tmp` could be `MODULUS` if `self` was zero. Create a mask that is
 	// zero if `self` was zero, and `u64::max_value()` if self was nonzero.
+	nz := a[0] | a[1] | a[2] | a[3]
+	mask := -((nz | -nz) >> 63)
+
 	f := &Fq{0, 0, 0, 0}
 	f[0] = d0 & mask
 	f[1] = d1 & mask
@@ -126,6 +159,15 @@ func (a *Fq) Equal(b *Fq) bool {
 	return a[0] == b[0] && a[1] == b[1] && a[2] == b[2] && a[3] == b[3]
 }
 
+// ConstantTimeEq returns 1 if a == b and 0 otherwise. Unlike Equal it
+// does not branch on the limbs of a and b.
+func (a *Fq) ConstantTimeEq(b *Fq) int {
+	x := (a[0] ^ b[0]) | (a[1] ^ b[1]) | (a[2] ^ b[2]) | (a[3] ^ b[3])
+
+	// x | -x has its top bit set unless x is zero
+	return int(1 ^ ((x | -x) >> 63))
+}
+
 func (a *Fq) Square() *Fq {
 	r1, carry := futil.Mac(0, a[0], a[1], 0)
 	r2, carry := futil.Mac(0, a[0], a[2], carry)
@@ -165,76 +207,57 @@ func (a *Fq) Square() *Fq {
 	return f
 }
 
+// Sqrt returns a square root of f using the constant-time Tonelli-Shanks
+// algorithm of RFC 9380, appendix I.4: the sequence of field operations
+// does not depend on f. If f is not a square the result is not a square
+// root of it, so callers must check it.
 func (f *Fq) Sqrt() *Fq {
-	w := f.PowVarTime([4]uint64{0x7fff2dff7fffffff, 0x04d0ec02a9ded201, 0x94cebea4199cec04, 0x0000000039f6d3a9})
-
-	v := S
-	x := f.Mul(w)
-	b := x.Mul(w)
-
-	z := &ROOTOFUNITY
-
-	for i := S; i > 0; i-- {
-		k := 1
-		tmp := b.Square()
-		j_less_than_v := 1
-
-		for j := 2; j < i; j++ {
-			tmp_is_one := 0
-			if tmp.Equal(One()) {
-				tmp_is_one = 1
-			}
-			squared := ConditionalSelect(tmp, z, tmp_is_one).Square()
-			tmp = ConditionalSelect(squared, tmp, tmp_is_one)
-			new_z := ConditionalSelect(z, squared, tmp_is_one)
-			jv := 0
-			if j == v {
-				jv = 1
-			}
-			// invert
-			var jvi int
-			if jv == 0 {
-				jvi = 1
-			}
-			j_less_than_v &= jvi
-			if tmp_is_one == 0 {
-				k = j
-			}
-			z = ConditionalSelect(z, new_z, j_less_than_v)
+	z := f.Pow(sqrtC3)
+	t := z.Square().Mul(f)
+	z = z.Mul(f)
+	b := Set(t)
+	c := Set(&rootOfUnity)
+
+	for i := S; i >= 2; i-- {
+		for j := 1; j <= i-2; j++ {
+			b = b.Square()
 		}
-
-		bchoice := 0
-		if b.Equal(One()) {
-			bchoice = 1
-		}
-		result := x.Mul(z)
-		x = ConditionalSelect(result, x, bchoice)
-		z = z.Square()
-		b = b.Mul(z)
-		v = k
+		e := b.ConstantTimeEq(&montR)
+		z = ConditionalSelect(z.Mul(c), z, e)
+		c = c.Square()
+		t = ConditionalSelect(t.Mul(c), t, e)
+		b = Set(t)
 	}
-	return x
+	return z
 }
 
-func (f *Fq) SqrtVarTime() *Fq {
+// IsSquare returns 1 if f is zero or a quadratic residue and 0 otherwise.
+// It computes f^((q-1)/2) with Pow (RFC 9380, appendix I.5).
+func (f *Fq) IsSquare() int {
+	l := f.Pow(legendreExp)
+	return l.ConstantTimeEq(&zero) | l.ConstantTimeEq(&montR)
+}
+
+// SqrtVarTime returns a square root of f, or ErrNonSquare if f is not
+// a square
+func (f *Fq) SqrtVarTime() (*Fq, error) {
 	one := One()
 	zero := &Fq{0, 0, 0, 0}
 
 	lgs := f.LegendreSymbolVarTime()
 
 	if lgs.Equal(zero) {
-		return f
+		return zero, nil
 	}
 	if !lgs.Equal(one) {
-		f = nil
-		return nil // XXX: We should bubble up an error for this
+		return nil, ErrNonSquare
 	}
 
 	r := f.PowVarTime([4]uint64{0x7fff2dff80000000, 0x04d0ec02a9ded201, 0x94cebea4199cec04, 0x0000000039f6d3a9})
 
 	t := f.PowVarTime([4]uint64{0xfffe5bfeffffffff, 0x09a1d80553bda402, 0x299d7d483339d808, 0x0000000073eda753})
 
-	c := &ROOTOFUNITY
+	c := &rootOfUnity
 	m := S
 
 	for !t.Equal(one) {
@@ -259,18 +282,29 @@ func (f *Fq) SqrtVarTime() *Fq {
 		m = i
 	}
 
-	return r
+	return r, nil
 }
 
 func (f *Fq) LegendreSymbolVarTime() *Fq {
 	// Legendre symbol computed via Euler's criterion:
 	// self^((q - 1) // 2)
-	return f.PowVarTime([4]uint64{
-		0x7fffffff80000000,
-		0xa9ded2017fff2dff,
-		0x199cec0404d0ec02,
-		0x39f6d3a994cebea4,
-	})
+	return f.PowVarTime(legendreExp)
+}
+
+// Pow returns f^b, where b is given as little-endian limbs. Unlike
+// PowVarTime it squares and multiplies for every bit of b, so the
+// sequence of field operations depends on neither f nor b.
+func (f *Fq) Pow(b [4]uint64) *Fq {
+	res := One()
+
+	for j := range b {
+		e := b[len(b)-1-j] // reversed
+		for i := 63; i >= 0; i-- {
+			res = res.Square()
+			res = ConditionalSelect(res, res.Mul(f), int((e>>uint64(i))&1))
+		}
+	}
+	return res
 }
 
 func (f *Fq) PowVarTime(b [4]uint64) *Fq {
@@ -289,8 +323,9 @@ func (f *Fq) PowVarTime(b [4]uint64) *Fq {
 	return res
 }
 
-// Inverse inverts a field element
-// If element is zero, it will return nil
+// Inverse inverts a field element by computing a^(q-2) with a fixed
+// addition chain, so the sequence of field operations does not depend
+// on a. Zero is mapped to zero.
 func (a *Fq) Inverse() *Fq {
 	sqrMulti := func(e *Fq, n int) *Fq {
 		for i := 0; i < n; i++ {
@@ -405,7 +440,7 @@ func Zero() *Fq {
 // One sets f to the one element
 func One() *Fq {
 	f := &Fq{1, 0, 0, 0}
-	copy(f[:], R[:])
+	copy(f[:], montR[:])
 	return f
 }
 
@@ -467,17 +502,16 @@ func (f *Fq) Bytes() []byte {
 	return buf[:]
 }
 
+// ConditionalSelect returns a copy of a if choice is 0 and of b if choice
+// is 1, without branching on choice
 func ConditionalSelect(a, b *Fq, choice int) *Fq {
-	tmp := a
-	if choice == 1 {
-		tmp = b
-	}
+	mask := -uint64(choice)
 
 	f := &Fq{0, 0, 0, 0}
-	f[0] = tmp[0]
-	f[1] = tmp[1]
-	f[2] = tmp[2]
-	f[3] = tmp[3]
+	f[0] = a[0] ^ (mask & (a[0] ^ b[0]))
+	f[1] = a[1] ^ (mask & (a[1] ^ b[1]))
+	f[2] = a[2] ^ (mask & (a[2] ^ b[2]))
+	f[3] = a[3] ^ (mask & (a[3] ^ b[3]))
 	return f
 }
 
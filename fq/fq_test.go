@@ -0,0 +1,153 @@
+package fq
+
+import (
+	"crypto/sha512"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestConstantsAreCopied(t *testing.T) {
+	for _, f := range []*Fq{One(), Zero(), D(), D2()} {
+		f[0] ^= 1
+	}
+	if *One() != montR || *Zero() != zero || *D() != d || *D2() != d2 {
+		t.Errorf("mutating a returned constant changed the constant")
+	}
+}
+
+func TestOperandsAreNotModified(t *testing.T) {
+	a := D()
+	b := One().Double()
+	ops := []func(){
+		func() { a.Add(b) },
+		func() { a.Sub(b) },
+		func() { a.Mul(b) },
+		func() { a.Neg() },
+		func() { a.Square() },
+		func() { a.Double() },
+		func() { a.Inverse() },
+		func() { a.Sqrt() },
+		func() { a.SqrtVarTime() },
+		func() { a.Pow(legendreExp) },
+		func() { a.IsSquare() },
+		func() { ConditionalSelect(a, b, 1) },
+	}
+	for i, op := range ops {
+		op()
+		if *a != d || *b != *One().Add(One()) {
+			t.Fatalf("operation %d modified its operands", i)
+		}
+	}
+
+	// SqrtVarTime used to return its receiver for zero
+	z := Zero()
+	if r, _ := z.SqrtVarTime(); r == z {
+		t.Errorf("SqrtVarTime returned its receiver")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	// d is not a square, so -1/d is not either and 1 + d.v^2 never vanishes
+	if _, err := D().SqrtVarTime(); err != ErrNonSquare {
+		t.Errorf("expected ErrNonSquare, got %v", err)
+	}
+	if r, err := D().Square().SqrtVarTime(); err != nil || !r.Square().Equal(D().Square()) {
+		t.Errorf("SqrtVarTime(d^2) = %v, %v", r, err)
+	}
+
+	if _, err := FromBytes(make([]byte, 31)); err != ErrInvalidLength {
+		t.Errorf("expected ErrInvalidLength, got %v", err)
+	}
+	if _, err := FromBytesWide(make([]byte, 32)); err != ErrInvalidLength {
+		t.Errorf("expected ErrInvalidLength, got %v", err)
+	}
+}
+
+func TestConcurrentSqrt(t *testing.T) {
+	x := D().Square()
+	want := x.Sqrt()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if got := x.Sqrt(); !got.Equal(want) {
+					t.Errorf("concurrent Sqrt gave %s, want %s", got, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestFromBytesWideReduces(t *testing.T) {
+	q, _ := new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+	for i := 0; i < 16; i++ {
+		digest := sha512.Sum512([]byte{byte(i)})
+		if i == 0 {
+			for j := range digest {
+				digest[j] = 0xff
+			}
+		}
+		f, err := FromBytesWide(digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		be := make([]byte, 64)
+		for j := range digest {
+			be[63-j] = digest[j]
+		}
+		want := new(big.Int).Mod(new(big.Int).SetBytes(be), q)
+		le := f.Bytes()
+		for l, r := 0, len(le)-1; l < r; l, r = l+1, r-1 {
+			le[l], le[r] = le[r], le[l]
+		}
+		if got := new(big.Int).SetBytes(le); got.Cmp(want) != 0 {
+			t.Errorf("FromBytesWide(%x) = %x, want %x", digest, got, want)
+		}
+	}
+}
+
+func TestConstantTimeHelpers(t *testing.T) {
+	one := One()
+	for i := 0; i < 16; i++ {
+		digest := sha512.Sum512([]byte{byte(i)})
+		f, _ := FromBytesWide(digest[:])
+
+		if got, want := f.Pow(legendreExp), f.LegendreSymbolVarTime(); !got.Equal(want) {
+			t.Errorf("Pow(%s) = %s, want %s", f, got, want)
+		}
+
+		want := 1
+		if _, err := f.SqrtVarTime(); err != nil {
+			want = 0
+		}
+		if got := f.IsSquare(); got != want {
+			t.Errorf("IsSquare(%s) = %d, want %d", f, got, want)
+		}
+
+		sq := f.Square()
+		if r := sq.Sqrt(); !r.Square().Equal(sq) {
+			t.Errorf("Sqrt(%s) = %s is not a square root", sq, r)
+		}
+
+		if f.ConstantTimeEq(f.Add(one)) != 0 || f.ConstantTimeEq(Set(f)) != 1 {
+			t.Errorf("ConstantTimeEq disagrees with Equal for %s", f)
+		}
+		if !ConditionalSelect(f, one, 0).Equal(f) || !ConditionalSelect(f, one, 1).Equal(one) {
+			t.Errorf("ConditionalSelect picked the wrong operand")
+		}
+	}
+
+	if Zero().IsSquare() != 1 || D().IsSquare() != 0 {
+		t.Errorf("IsSquare(0) or IsSquare(d) is wrong")
+	}
+	if r := Zero().Sqrt(); !r.Equal(Zero()) {
+		t.Errorf("Sqrt(0) = %s", r)
+	}
+}
@@ -16,33 +16,31 @@ var r = Fr{
 	0x0e7db4ea6533afa9,
 }
 
-/// R = 2^256 mod r
-var R = Fr{
+// montR = 2^256 mod r
+var montR = Fr{
 	0x25f8_0bb3_b996_07d9,
 	0xf315_d62f_66b6_e750,
 	0x9325_14ee_eb88_14f4,
 	0x09a6_fc6f_4791_55c6,
 }
 
-/// R^2 = 2^512 mod r
-var R2 = Fr{
+// montR2 = 2^512 mod r
+var montR2 = Fr{
 	0x67719aa495e57731,
 	0x51b0cef09ce3fc26,
 	0x69dab7fac026e9a5,
 	0x04f6547b8d127688,
 }
 
-/// R^3 = 2^768 mod r
-var R3 = Fr{
+// montR3 = 2^768 mod r
+var montR3 = Fr{
 	0xe0d6c6563d830544,
 	0x323e3883598d0f85,
 	0xf0fea3004c2e2ba8,
 	0x05874f84946737ec,
 }
 
-var MODULUS = Fr{
-	0xd097_0e5e_d6f7_2cb7,
-	0xa668_2093_ccc8_1082,
-	0x0667_3b01_0134_3b00,
-	0x0e7d_b4ea_6533_afa9,
+// Modulus returns r as 32 little-endian bytes
+func Modulus() []byte {
+	return r.BytesNotCanonical()
 }
@@ -4,12 +4,20 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 
+	"github.com/mechanizm/jubjub"
 	"github.com/mechanizm/jubjub/futil"
 )
 
 type Fr [4]uint64
 
-func FromBytes(byt []byte) *Fr {
+var ErrInvalidLength = jubjub.ErrInvalidLength
+
+// FromBytes reduces a 32 byte little-endian integer modulo r
+func FromBytes(byt []byte) (*Fr, error) {
+	if len(byt) != 32 {
+		return nil, ErrInvalidLength
+	}
+
 	d := &Fr{0, 0, 0, 0}
 
 	d[0] = binary.LittleEndian.Uint64(byt[0:8])
@@ -18,10 +26,15 @@ func FromBytes(byt []byte) *Fr {
 	d[3] = binary.LittleEndian.Uint64(byt[24:32])
 
 	// Convert to Montgomery form
-	return d.Mul(&R2)
+	return d.Mul(&montR2), nil
 }
 
-func FromBytesWide(byt []byte) *Fr {
+// FromBytesWide reduces a 64 byte little-endian integer modulo r
+func FromBytesWide(byt []byte) (*Fr, error) {
+	if len(byt) != 64 {
+		return nil, ErrInvalidLength
+	}
+
 	d0 := &Fr{0, 0, 0, 0}
 	d1 := &Fr{0, 0, 0, 0}
 
@@ -36,10 +49,10 @@ func FromBytesWide(byt []byte) *Fr {
 	d1[3] = binary.LittleEndian.Uint64(byt[56:64])
 
 	// Convert to Montgomery form
-	d0 = d0.Mul(&R2)
-	d1 = d1.Mul(&R3)
+	d0 = d0.Mul(&montR2)
+	d1 = d1.Mul(&montR3)
 
-	return d0.Add(d1)
+	return d0.Add(d1), nil
 }
 
 func Zero() *Fr {
@@ -47,7 +60,8 @@ func Zero() *Fr {
 }
 
 func One() *Fr {
-	return &R
+	f := montR
+	return &f
 }
 
 func (lhs *Fr) Add(rhs *Fr) *Fr {
@@ -89,10 +103,10 @@ func (lhs *Fr) Sub(rhs *Fr) *Fr {
 }
 
 func (f *Fr) Neg() *Fr {
-	d0, borrow := futil.Sbb(MODULUS[0], f[0], 0)
-	d1, borrow := futil.Sbb(MODULUS[1], f[1], borrow)
-	d2, borrow := futil.Sbb(MODULUS[2], f[2], borrow)
-	d3, _ := futil.Sbb(MODULUS[3], f[3], borrow)
+	d0, borrow := futil.Sbb(r[0], f[0], 0)
+	d1, borrow := futil.Sbb(r[1], f[1], borrow)
+	d2, borrow := futil.Sbb(r[2], f[2], borrow)
+	d3, _ := futil.Sbb(r[3], f[3], borrow)
 
 	msk := f[0]|f[1]|f[2]|f[3] == 0
 	var mask uint64
@@ -0,0 +1,40 @@
+package fr
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConstantsAreCopied(t *testing.T) {
+	one := One()
+	one[0] ^= 1
+	if *One() != montR {
+		t.Errorf("mutating the result of One changed One")
+	}
+
+	m := Modulus()
+	m[0] ^= 1
+	if Modulus()[0] != 0xb7 {
+		t.Errorf("mutating the result of Modulus changed Modulus")
+	}
+}
+
+func TestConcurrentArithmetic(t *testing.T) {
+	want := testScalars()[5].Mul(testScalars()[6]).Add(One())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				a, b := testScalars()[5], testScalars()[6]
+				if got := a.Mul(b).Add(One()); *got != *want {
+					t.Errorf("concurrent arithmetic gave %s, want %s", got, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
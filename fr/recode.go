@@ -0,0 +1,87 @@
+package fr
+
+import (
+	"encoding/binary"
+
+	"github.com/mechanizm/jubjub"
+)
+
+var ErrInvalidWidth = jubjub.ErrInvalidWidth
+
+// Bits returns the canonical value of f as 256 little-endian bits
+func (f *Fr) Bits() [256]bool {
+	var bits [256]bool
+	for i, byt := range f.Bytes() {
+		for j := 0; j < 8; j++ {
+			bits[8*i+j] = (byt>>j)&1 == 1
+		}
+	}
+	return bits
+}
+
+// ToWNAF returns the width-w non-adjacent form of f, little-endian. Every
+// non-zero digit is odd and smaller than 2^(w-1) in absolute value, and
+// any w consecutive digits contain at most one non-zero digit. w must be
+// between 2 and 8, otherwise ErrInvalidWidth is returned.
+func (f *Fr) ToWNAF(w uint) ([256]int8, error) {
+	var naf [256]int8
+	if w < 2 || w > 8 {
+		return naf, ErrInvalidWidth
+	}
+
+	buf := f.Bytes()
+	var x [5]uint64
+	for i := 0; i < 4; i++ {
+		x[i] = binary.LittleEndian.Uint64(buf[8*i:])
+	}
+
+	width := uint64(1) << w
+	windowMask := width - 1
+
+	carry := uint64(0)
+	for pos := uint(0); pos < 256; {
+		idx, bit := pos/64, pos%64
+		var bitBuf uint64
+		if bit < 64-w {
+			bitBuf = x[idx] >> bit
+		} else {
+			bitBuf = x[idx]>>bit | x[idx+1]<<(64-bit)
+		}
+
+		window := carry + bitBuf&windowMask
+		if window&1 == 0 {
+			// The digit is zero, keep the carry and move on
+			pos++
+			continue
+		}
+
+		if window < width/2 {
+			carry = 0
+			naf[pos] = int8(window)
+		} else {
+			carry = 1
+			naf[pos] = int8(int64(window) - int64(width))
+		}
+		pos += w
+	}
+	return naf, nil
+}
+
+// ToRadix16Signed returns digits d[i] in [-8, 8) such that
+// f = sum(d[i].16^i). The recoding has no data-dependent branches.
+func (f *Fr) ToRadix16Signed() [64]int8 {
+	var digits [64]int8
+	for i, byt := range f.Bytes() {
+		digits[2*i] = int8(byt & 15)
+		digits[2*i+1] = int8(byt >> 4)
+	}
+
+	// f < 2^252, so the last digit absorbs the final carry without
+	// overflowing
+	for i := 0; i < 63; i++ {
+		carry := (digits[i] + 8) >> 4
+		digits[i] -= carry << 4
+		digits[i+1] += carry
+	}
+	return digits
+}
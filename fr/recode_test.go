@@ -0,0 +1,113 @@
+package fr
+
+import (
+	"crypto/sha512"
+	"testing"
+)
+
+func testScalars() []*Fr {
+	scalars := []*Fr{Zero(), One(), One().Neg(), One().Double()}
+	for i := 0; i < 16; i++ {
+		h := sha512.Sum512([]byte{byte(i)})
+		s, _ := FromBytesWide(h[:])
+		scalars = append(scalars, s)
+	}
+	return scalars
+}
+
+// fromSmall returns d as an element of Fr
+func fromSmall(d int) *Fr {
+	byt := make([]byte, 32)
+	byt[0] = byte(abs(d))
+	f, _ := FromBytes(byt)
+	if d < 0 {
+		return f.Neg()
+	}
+	return f
+}
+
+func abs(d int) int {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// evaluate returns sum(digits[i].radix^i)
+func evaluate(digits []int, radix int) *Fr {
+	acc := Zero()
+	for i := len(digits) - 1; i >= 0; i-- {
+		acc = acc.Mul(fromSmall(radix)).Add(fromSmall(digits[i]))
+	}
+	return acc
+}
+
+func TestBits(t *testing.T) {
+	for _, f := range testScalars() {
+		bits := f.Bits()
+		digits := make([]int, len(bits))
+		for i, b := range bits {
+			if b {
+				digits[i] = 1
+			}
+		}
+		if got := evaluate(digits, 2); *got != *f {
+			t.Errorf("Bits(%s) evaluates to %s", f, got)
+		}
+	}
+}
+
+func TestToWNAF(t *testing.T) {
+	for _, w := range []uint{2, 3, 4, 5, 6, 7, 8} {
+		for _, f := range testScalars() {
+			naf, err := f.ToWNAF(w)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			digits := make([]int, len(naf))
+			last := -1
+			for pos, d := range naf {
+				digits[pos] = int(d)
+				if d == 0 {
+					continue
+				}
+				if d%2 == 0 || abs(int(d)) >= 1<<(w-1) {
+					t.Errorf("w = %d: invalid digit %d in wNAF of %s", w, d, f)
+				}
+				if last >= 0 && pos-last < int(w) {
+					t.Errorf("w = %d: non-zero digits at %d and %d in wNAF of %s", w, last, pos, f)
+				}
+				last = pos
+			}
+
+			if got := evaluate(digits, 2); *got != *f {
+				t.Errorf("w = %d: wNAF of %s evaluates to %s", w, f, got)
+			}
+		}
+	}
+
+	for _, w := range []uint{0, 1, 9, 64} {
+		if _, err := One().ToWNAF(w); err != ErrInvalidWidth {
+			t.Errorf("w = %d: expected ErrInvalidWidth, got %v", w, err)
+		}
+	}
+}
+
+func TestToRadix16Signed(t *testing.T) {
+	for _, f := range testScalars() {
+		radix16 := f.ToRadix16Signed()
+
+		digits := make([]int, len(radix16))
+		for i, d := range radix16 {
+			if d < -8 || d >= 8 {
+				t.Errorf("invalid digit %d in radix 16 form of %s", d, f)
+			}
+			digits[i] = int(d)
+		}
+
+		if got := evaluate(digits, 16); *got != *f {
+			t.Errorf("radix 16 form of %s evaluates to %s", f, got)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package fr
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/mechanizm/jubjub"
+)
+
+var ErrNonCanonical = jubjub.ErrNonCanonical
+
+// ScalarBytes is the canonical 32 byte little-endian encoding of a
+// scalar. It is comparable, so it can be used as a map key.
+type ScalarBytes [32]byte
+
+// ScalarBytes returns the canonical encoding of f
+func (f *Fr) ScalarBytes() ScalarBytes {
+	var b ScalarBytes
+	copy(b[:], f.Bytes())
+	return b
+}
+
+// FromScalarBytes decodes a scalar, rejecting encodings of integers
+// that are not smaller than r with ErrNonCanonical
+func FromScalarBytes(b ScalarBytes) (*Fr, error) {
+	f, _ := FromBytes(b[:])
+	if !bytes.Equal(f.Bytes(), b[:]) {
+		return nil, ErrNonCanonical
+	}
+	return f, nil
+}
+
+// ScalarBytesFromHex parses the hex encoding of 32 bytes, in the same
+// byte order as ScalarBytes.String
+func ScalarBytesFromHex(s string) (ScalarBytes, error) {
+	var b ScalarBytes
+	err := b.UnmarshalText([]byte(s))
+	return b, err
+}
+
+// String returns the bytes of b in hex. Unlike Fr.String, the bytes are
+// not reversed.
+func (b ScalarBytes) String() string {
+	return hex.EncodeToString(b[:])
+}
+
+func (b ScalarBytes) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+func (b *ScalarBytes) UnmarshalText(text []byte) error {
+	if len(text) != 2*len(b) {
+		return ErrInvalidLength
+	}
+	var tmp ScalarBytes
+	if _, err := hex.Decode(tmp[:], text); err != nil {
+		return err
+	}
+	*b = tmp
+	return nil
+}
+
+func (b ScalarBytes) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, b[:]...), nil
+}
+
+func (b *ScalarBytes) UnmarshalBinary(data []byte) error {
+	if len(data) != len(b) {
+		return ErrInvalidLength
+	}
+	copy(b[:], data)
+	return nil
+}
@@ -0,0 +1,60 @@
+package fr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestScalarBytesRoundTrip(t *testing.T) {
+	for _, f := range testScalars() {
+		b := f.ScalarBytes()
+		g, err := FromScalarBytes(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *g != *f {
+			t.Errorf("FromScalarBytes(%s) = %s, want %s", b, g, f)
+		}
+
+		h, err := ScalarBytesFromHex(b.String())
+		if err != nil || h != b {
+			t.Errorf("ScalarBytesFromHex(%s) = %s, %v", b, h, err)
+		}
+	}
+}
+
+func TestScalarBytesNonCanonical(t *testing.T) {
+	var b ScalarBytes
+	copy(b[:], Modulus())
+	if _, err := FromScalarBytes(b); err != ErrNonCanonical {
+		t.Errorf("expected ErrNonCanonical for r, got %v", err)
+	}
+
+	b[0]--
+	if f, err := FromScalarBytes(b); err != nil || *f != *One().Neg() {
+		t.Errorf("FromScalarBytes(r - 1) = %v, %v", f, err)
+	}
+}
+
+func TestScalarBytesMarshaling(t *testing.T) {
+	b := One().ScalarBytes()
+
+	text, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(text) != `"0100000000000000000000000000000000000000000000000000000000000000"` {
+		t.Errorf("json.Marshal(1) = %s", text)
+	}
+	var decoded ScalarBytes
+	if err := json.Unmarshal(text, &decoded); err != nil || decoded != b {
+		t.Errorf("json.Unmarshal = %s, %v", decoded, err)
+	}
+
+	if err := decoded.UnmarshalBinary(make([]byte, 33)); err != ErrInvalidLength {
+		t.Errorf("expected ErrInvalidLength, got %v", err)
+	}
+	if err := decoded.UnmarshalText([]byte("01")); err != ErrInvalidLength {
+		t.Errorf("expected ErrInvalidLength, got %v", err)
+	}
+}
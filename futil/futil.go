@@ -1,37 +1,33 @@
 package futil
 
+import "math/bits"
+
 // Adc Computes a + b + carry, returning the result and the new carry over.
+// Like the helpers below it uses math/bits, whose execution time does not
+// depend on the inputs.
 func Adc(a, b, carry uint64) (uint64, uint64) {
-	res := FromU64(a)
-	res = res.Add(b)
-	res = res.Add(carry)
+	sum, c1 := bits.Add64(a, b, 0)
+	sum, c2 := bits.Add64(sum, carry, 0)
 
-	return res.L, res.H
+	return sum, c1 + c2
 }
 
 // Sbb Computes a - (b + borrow), returning the result and the new borrow.
+// Only the top bit of borrow is used, and the new borrow is either zero or
+// all ones.
 func Sbb(a, b, borrow uint64) (uint64, uint64) {
+	diff, borrowOut := bits.Sub64(a, b, borrow>>63)
 
-	a128 := FromU64(a)
-	b128 := FromU64(b)
-	borr128 := FromU64(borrow >> 63)
-
-	bBor := b128.AddU128(borr128)
-	res := a128.SubU128(bBor)
-
-	return res.L, res.H
-
+	return diff, -borrowOut
 }
 
 // Mac Computes a + (b * c) + carry, returning the result and the new carry over.
 func Mac(a, b, c, carry uint64) (uint64, uint64) {
+	hi, lo := bits.Mul64(b, c)
+	lo, c1 := bits.Add64(lo, a, 0)
+	lo, c2 := bits.Add64(lo, carry, 0)
 
-	res := FromU64(b)
-	res = res.MulU64(c)
-	res = res.Add(a)
-	res = res.Add(carry)
-
-	return res.L, res.H
+	return lo, hi + c1 + c2
 }
 
 // Load4 interprets a 4-byte unsigned little endian byte-slice as uint64
@@ -39,3 +35,11 @@ func Load4(b []byte) uint64 {
 	return (uint64(b[0]) | (uint64(b[1]) << 8) |
 		(uint64(b[2]) << 16) | (uint64(b[3]) << 24))
 }
+
+// Reverse reverses b in place, converting between little-endian and
+// big-endian byte orders
+func Reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
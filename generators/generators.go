@@ -0,0 +1,119 @@
+// Package generators exposes the fixed generators of the Sapling
+// protocol (Zcash protocol specification, section 5.4.8). Each one is
+// the output of GroupHash with a fixed personalization and input, which
+// generators_test.go re-derives with the grouphash package.
+package generators
+
+import (
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fq"
+)
+
+// The coordinates (u, v) of every generator are in Montgomery form
+var (
+	// spendingKeyGenerator = GroupHash("Zcash_G_", "")
+	spendingKeyGenerator = [2]fq.Fq{
+		{0x9fea675eb63e8cf6, 0x15ba8508eb7f13c5, 0x87a02da79c8b7ef8, 0x0af4897169c1851e},
+		{0xfb63146264e65a56, 0x77f3f8c6fd45d5e5, 0x8770a243986a6eb9, 0x6dde055ca112d037},
+	}
+	// proofGenerationKeyGenerator = GroupHash("Zcash_H_", "")
+	proofGenerationKeyGenerator = [2]fq.Fq{
+		{0x41104aa2a28ce9c9, 0xc462a468a39d3bce, 0xca0b2f2590949e88, 0x44bfe9f0050f1328},
+		{0x5a58255c34150b48, 0x903e00e0a6e9828b, 0xb5ae92fa64e8aed6, 0x2d8b297cebe14767},
+	}
+	// valueCommitmentValueBase = GroupHash("Zcash_cv", "v")
+	valueCommitmentValueBase = [2]fq.Fq{
+		{0x409ce81837df79f0, 0x3c98c0e3fc5dfcb8, 0x0e204bfd741ddaa8, 0x6c83d46951e5b244},
+		{0xaddb5bd27e1d7ac4, 0xc4829b95f98ff2da, 0x5c6a2b213eaea041, 0x11e824759f43a417},
+	}
+	// valueCommitmentRandomnessBase = GroupHash("Zcash_cv", "r")
+	valueCommitmentRandomnessBase = [2]fq.Fq{
+		{0x4d03ed9691891e92, 0xba4cc5c9a5b97f0a, 0xd9d624953b9173b8, 0x41b89f72efc219e8},
+		{0x8451639f6b76734d, 0xc4ec61fc3b36434f, 0xa4b3a5ecaacdaf22, 0x270e367eaa7cede1},
+	}
+	// noteCommitmentRandomnessBase = GroupHash("Zcash_PH", "r")
+	noteCommitmentRandomnessBase = [2]fq.Fq{
+		{0xf232b3c73857ae67, 0xaa21db0f9168deab, 0x0bdbf016811cea81, 0x66cfa5443a48264d},
+		{0x144e989af40de090, 0xcc20b26d43c78386, 0x543b4aa373c046c5, 0x1dc20bd4ad9681f4},
+	}
+	// nullifierPositionGenerator = GroupHash("Zcash_J_", "")
+	nullifierPositionGenerator = [2]fq.Fq{
+		{0xd847b009ea335bd0, 0x03ff690adc017171, 0x460a7bd2246c4a96, 0x71fe0dc5b631e361},
+		{0x6ff250e81ff0129c, 0x92ff360596855343, 0xc627e405400d6de2, 0x4ab7fabcab0bb323},
+	}
+
+	// pedersenHashGenerators[i] = GroupHash("Zcash_PH", LE32(i))
+	pedersenHashGenerators = [6][2]fq.Fq{
+		{
+			{0xd74dd031cd264b2d, 0x1cb83668c82f682a, 0x499100ab1baa6078, 0x6f8109ae8081922b},
+			{0x5bac9c55cca99349, 0x379fcdfef00a5e56, 0x74a521bc2b88a94e, 0x50d5c0adf21ed513},
+		},
+		{
+			{0x3fa6ba2874be774c, 0x4f08833fbcf0f0b6, 0x50dcec31790564e9, 0x1f0181746c6229d7},
+			{0x38e9f08faade5b26, 0xa42fcb2b32f4dec5, 0x25e316d6911b4bbe, 0x4f3e6915adf68877},
+		},
+		{
+			{0xd716caa463b6c374, 0x804c063a55fbcb71, 0xabd118e6fa57e722, 0x63c8064c96b5076a},
+			{0xf40c921503cdc77e, 0xe815896d9db1d3fb, 0xd856f204037ab9cb, 0x28fc24c59736ae1e},
+		},
+		{
+			{0xcdb4c93fcb99bd60, 0x32b0b70edf9757c8, 0x44c65d077f9b7705, 0x2668cf506026bf29},
+			{0xe01a6324d18f6b49, 0x45e2519f61a32243, 0xc4cb45ad1fa93303, 0x32a254f600d69162},
+		},
+		{
+			{0xc558cb5f1ebc9084, 0xebf89285ec323f1c, 0xdd1ca2f7d592bd74, 0x6271c9edff113e8b},
+			{0x9399eec176e738a1, 0x4852242bc5ff29db, 0x1e18b1baa4509f39, 0x02a6db758969812f},
+		},
+		{
+			{0xa1f75a0483807a23, 0xe3edb26861bba66a, 0xa8c8cf1d8ccec421, 0x010d63a0f7301f0f},
+			{0xfd01f6029334c34e, 0xc30dd584b45f8378, 0x15a7f3e413a95b34, 0x120ae95a0a8fd2db},
+		},
+	}
+)
+
+// SpendingKeyGenerator returns the generator of spend authorization keys, ak = [ask]G
+func SpendingKeyGenerator() *extended.ExtendedPoint {
+	return point(&spendingKeyGenerator)
+}
+
+// ProofGenerationKeyGenerator returns the generator of nullifier deriving keys, nk = [nsk]H
+func ProofGenerationKeyGenerator() *extended.ExtendedPoint {
+	return point(&proofGenerationKeyGenerator)
+}
+
+// ValueCommitmentValueBase returns the base that value commitments multiply the value by
+func ValueCommitmentValueBase() *extended.ExtendedPoint {
+	return point(&valueCommitmentValueBase)
+}
+
+// ValueCommitmentRandomnessBase returns the base that value commitments multiply the trapdoor by
+func ValueCommitmentRandomnessBase() *extended.ExtendedPoint {
+	return point(&valueCommitmentRandomnessBase)
+}
+
+// NoteCommitmentRandomnessBase returns the base that note commitments multiply the trapdoor by
+func NoteCommitmentRandomnessBase() *extended.ExtendedPoint {
+	return point(&noteCommitmentRandomnessBase)
+}
+
+// NullifierPositionGenerator returns the base that the note position is multiplied by when deriving nullifiers
+func NullifierPositionGenerator() *extended.ExtendedPoint {
+	return point(&nullifierPositionGenerator)
+}
+
+// PedersenHashGenerators returns the generators of the Sapling Pedersen
+// hash, one per segment of the input
+func PedersenHashGenerators() []*extended.ExtendedPoint {
+	points := make([]*extended.ExtendedPoint, len(pedersenHashGenerators))
+	for i := range pedersenHashGenerators {
+		points[i] = point(&pedersenHashGenerators[i])
+	}
+	return points
+}
+
+// point returns a fresh copy of the point with coordinates c
+func point(c *[2]fq.Fq) *extended.ExtendedPoint {
+	u, v := c[0], c[1]
+	return extended.FromAffine(affine.FromRawUnchecked(&u, &v))
+}
@@ -0,0 +1,76 @@
+package generators
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/grouphash"
+)
+
+func TestGenerators(t *testing.T) {
+	vectors := []struct {
+		name            string
+		point           *extended.ExtendedPoint
+		personalization string
+		msg             []byte
+		encoding        string
+	}{
+		{"SpendingKeyGenerator", SpendingKeyGenerator(), "Zcash_G_", nil, "30b5f2aaad325630bcdddbce4d67656d05fd1cc2d037bb5375b6e96d9e01a1d7"},
+		{"ProofGenerationKeyGenerator", ProofGenerationKeyGenerator(), "Zcash_H_", nil, "e7e85de0f7f97a46d249a1f5ea51df50cc48490f8401c9de7a2adf1807d1b6d4"},
+		{"ValueCommitmentValueBase", ValueCommitmentValueBase(), "Zcash_cv", []byte("v"), "d7c86706f5817aa718cd1cfad03233bcd64a7789fd9422d3b17af6823a7e6ac6"},
+		{"ValueCommitmentRandomnessBase", ValueCommitmentRandomnessBase(), "Zcash_cv", []byte("r"), "8b6a0b38b9faae3c3b803b47b0f146ad50ab221e6e2afbe6dbde45cba9d381ed"},
+		{"NoteCommitmentRandomnessBase", NoteCommitmentRandomnessBase(), "Zcash_PH", []byte("r"), "ac776c796563fcd44cc49cfaea8bb796952c266e47779d94574c10ad01754b11"},
+		{"NullifierPositionGenerator", NullifierPositionGenerator(), "Zcash_J_", nil, "65002bc736faf7a3422effffe8b855e18fba96a0158a9efca584bf40549d36e1"},
+	}
+
+	pedersenHashEncodings := []string{
+		"ca3c2432d4abbf7732464ec08b2e47f95edc7e836b16c979571b52d3a2879ea8",
+		"9118bf4e3cc50d7be8d3fa98ebbe3a1f25d901c0421189f733fe435b7f8c5d01",
+		"57d493972c50ed8098b484177f2ab28b53e88c8e6ca400e09eee4ed200152eb6",
+		"e97035a3ec4b7184856a1fa1a1af0351b747d9d8cb0a0791d8ca564b0ce47e2f",
+		"ef8a65c3998296994cd1595809d8b9b3e5c90614383278390a9dab0321c54bc9",
+		"9a628d9f11826043a7136bc6d20002a8286a130a07b1cd64e5b6bfe88946ece4",
+	}
+	for i, p := range PedersenHashGenerators() {
+		msg := make([]byte, 4)
+		binary.LittleEndian.PutUint32(msg, uint32(i))
+		vectors = append(vectors, struct {
+			name            string
+			point           *extended.ExtendedPoint
+			personalization string
+			msg             []byte
+			encoding        string
+		}{"PedersenHashGenerators", p, "Zcash_PH", msg, pedersenHashEncodings[i]})
+	}
+
+	for _, v := range vectors {
+		hasher, err := grouphash.NewGroupHasher([]byte(v.personalization))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := hasher.FindGroupHash(v.msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !v.point.Equal(extended.FromJubjubPoint(want)) {
+			t.Errorf("%s(%x) does not match GroupHash", v.name, v.msg)
+		}
+		if got := hex.EncodeToString(v.point.Bytes()); got != v.encoding {
+			t.Errorf("%s(%x) encodes to %s, want %s", v.name, v.msg, got, v.encoding)
+		}
+		if !v.point.IsPrimeOrder() {
+			t.Errorf("%s(%x) is not of prime order", v.name, v.msg)
+		}
+	}
+}
+
+// extended cannot import this package, so it keeps its own copy of the
+// spending key generator for DoubleScalarMulVarTime
+func TestBasePointIsSpendingKeyGenerator(t *testing.T) {
+	if !extended.BasePoint().Equal(SpendingKeyGenerator()) {
+		t.Errorf("extended.BasePoint() = %s, want %s", extended.BasePoint(), SpendingKeyGenerator())
+	}
+}
@@ -1,14 +1,39 @@
 package grouphash
 
 import (
-	"fmt"
+	"errors"
 	"math/big"
 
 	"github.com/mechanizm/jubjub"
 	"github.com/mechanizm/jubjub/blake2s"
+	"github.com/mechanizm/jubjub/futil"
 )
 
-var ErrInvalidPoint = fmt.Errorf("invalid point")
+var (
+	// ErrInvalidPoint is returned by Hash when the digest does not give a
+	// point of the prime-order subgroup. The error also matches the
+	// underlying reason, jubjub.ErrNotOnCurve or jubjub.ErrSmallOrder.
+	ErrInvalidPoint = errors.New("grouphash: invalid point")
+
+	ErrGroupHashExhausted = jubjub.ErrGroupHashExhausted
+)
+
+// invalidPointError wraps the reason why a digest was rejected
+type invalidPointError struct {
+	err error
+}
+
+func (e *invalidPointError) Error() string {
+	return ErrInvalidPoint.Error() + ": " + e.err.Error()
+}
+
+func (e *invalidPointError) Unwrap() error {
+	return e.err
+}
+
+func (e *invalidPointError) Is(target error) bool {
+	return target == ErrInvalidPoint
+}
 
 var urs = []byte("096b36a5804bfacef1691e173c366a47ff5ba84a44f26ddd7e8d9f79d5b42df0")
 
@@ -18,31 +43,33 @@ type GroupHasher struct {
 	domain []byte
 }
 
+// NewGroupHasher returns a hasher for the given BLAKE2s personalization.
+// domain is copied, so the caller may reuse it.
 func NewGroupHasher(domain []byte) (*GroupHasher, error) {
 	j := jubjub.NewJubjub()
 
 	return &GroupHasher{
 		curve:  j,
-		domain: domain,
+		domain: append([]byte{}, domain...),
 	}, nil
 }
 
-func reverse(numbers []byte) {
-	for i, j := 0, len(numbers)-1; i < j; i, j = i+1, j-1 {
-		numbers[i], numbers[j] = numbers[j], numbers[i]
-	}
-}
-
+// FindGroupHash returns Hash(msg || i) for the first byte i that gives a
+// valid point, or ErrGroupHashExhausted if none of the 256 does
 func (hasher *GroupHasher) FindGroupHash(msg []byte) (*jubjub.JubjubPoint, error) {
-	for i := uint8(0); i <= 255; i++ {
-		msgWithIndex := append(msg, i)
+	for i := 0; i <= 255; i++ {
+		// Copy msg so that the index is never written into the
+		// caller's backing array
+		msgWithIndex := make([]byte, 0, len(msg)+1)
+		msgWithIndex = append(msgWithIndex, msg...)
+		msgWithIndex = append(msgWithIndex, byte(i))
 		p, err := hasher.Hash(msgWithIndex)
-		if err == ErrInvalidPoint {
+		if errors.Is(err, ErrInvalidPoint) {
 			continue
 		}
-		return p, nil
+		return p, err
 	}
-	return nil, fmt.Errorf("could not find a valid point")
+	return nil, ErrGroupHashExhausted
 }
 
 func (hasher *GroupHasher) Hash(msg []byte) (*jubjub.JubjubPoint, error) {
@@ -61,7 +88,7 @@ func (hasher *GroupHasher) Hash(msg []byte) (*jubjub.JubjubPoint, error) {
 	}
 
 	blakeHashBytes := blake.Sum(nil)
-	reverse(blakeHashBytes)
+	futil.Reverse(blakeHashBytes)
 
 	y := big.NewInt(0)
 	y.SetBytes(blakeHashBytes)
@@ -71,12 +98,12 @@ func (hasher *GroupHasher) Hash(msg []byte) (*jubjub.JubjubPoint, error) {
 
 	p, err := hasher.curve.GetForY(y, highestBit == 1)
 	if err != nil {
-		return nil, ErrInvalidPoint
+		return nil, &invalidPointError{err: err}
 	}
 
 	p2, err := hasher.curve.MulByCofactor(p)
 	if err != nil {
-		return nil, ErrInvalidPoint
+		return nil, &invalidPointError{err: err}
 	}
 
 	return p2, nil
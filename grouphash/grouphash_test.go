@@ -0,0 +1,100 @@
+package grouphash
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/mechanizm/jubjub"
+)
+
+func TestFindGroupHashDoesNotAlias(t *testing.T) {
+	hasher, err := NewGroupHasher([]byte("Zcash_G_"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// msg has spare capacity, which append would write the index into
+	backing := []byte{'m', 0xaa, 0xaa}
+	msg := backing[:1]
+
+	if _, err := hasher.FindGroupHash(msg); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(backing, []byte{'m', 0xaa, 0xaa}) {
+		t.Errorf("FindGroupHash wrote into the backing array of msg: %x", backing)
+	}
+}
+
+func TestNewGroupHasherCopiesDomain(t *testing.T) {
+	domain := []byte("Zcash_G_")
+	hasher, err := NewGroupHasher(domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := hasher.FindGroupHash([]byte("m"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	copy(domain, "Zcash_PH")
+	got, err := hasher.FindGroupHash([]byte("m"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("changing the domain slice changed the hasher's output")
+	}
+}
+
+func TestFindGroupHashConcurrent(t *testing.T) {
+	hasher, err := NewGroupHasher([]byte("Zcash_PH"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := make([]byte, 4, 8)
+	want, err := hasher.FindGroupHash(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := hasher.FindGroupHash(msg)
+			if err != nil || got.String() != want.String() {
+				t.Errorf("concurrent FindGroupHash gave %v, %v", got, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestHashErrors(t *testing.T) {
+	hasher, err := NewGroupHasher([]byte("Zcash_G_"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// About half of the digests are not the v-coordinate of a point
+	rejected := 0
+	for i := 0; i < 16; i++ {
+		_, err := hasher.Hash([]byte{byte(i)})
+		if err == nil {
+			continue
+		}
+		rejected++
+		if !errors.Is(err, ErrInvalidPoint) {
+			t.Errorf("Hash error %v is not ErrInvalidPoint", err)
+		}
+		if !errors.Is(err, jubjub.ErrNotOnCurve) && !errors.Is(err, jubjub.ErrNonCanonical) && !errors.Is(err, jubjub.ErrSmallOrder) {
+			t.Errorf("Hash error %v does not wrap its reason", err)
+		}
+	}
+	if rejected == 0 {
+		t.Errorf("no digest was rejected")
+	}
+}
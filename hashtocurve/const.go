@@ -0,0 +1,47 @@
+package hashtocurve
+
+import "github.com/mechanizm/jubjub/fq"
+
+// Jubjub is birationally equivalent to the Montgomery curve
+// K.t^2 = s^3 + J.s^2 + s with J = 40962 and K = -40964.
+// All constants are in Montgomery form.
+
+// z = 5, the non-square used by Elligator 2 (RFC 9380, appendix H.3)
+var z = fq.Fq{
+	0x0000_000a_ffff_fff5,
+	0x66d9_f3df_0012_0c0b,
+	0xcc83_b7a7_960b_b7c5,
+	0x04c9_cf6d_363b_9de5,
+}
+
+// jDivK = J / K
+var jDivK = fq.Fq{
+	0x9529_1229_dcba_7b59,
+	0x51f4_08fa_86cb_c158,
+	0x703e_55d2_6adc_ec6e,
+	0x1fea_22a7_a8a4_905f,
+}
+
+// minusJDivK = -J / K
+var minusJDivK = fq.Fq{
+	0x6ad6_edd5_2345_84a8,
+	0x01c9_9b08_7932_9aa6,
+	0xc2fb_8235_9ec4_eb97,
+	0x5403_84ab_80f8_ece8,
+}
+
+// invKSqr = 1 / K^2
+var invKSqr = fq.Fq{
+	0x777e_475e_fa15_a33e,
+	0x8c38_71d0_74fc_8a2c,
+	0xfa3e_e3c5_446b_f103,
+	0x0b15_9b38_94a7_3664,
+}
+
+// k = K = -40964
+var k = fq.Fq{
+	0xfffe_9ea4_0001_615c,
+	0x974f_1411_bc43_aea3,
+	0x2bac_b82b_a108_fa62,
+	0x5d21_ce45_1e59_9495,
+}
@@ -0,0 +1,213 @@
+// Package hashtocurve implements hash_to_curve and encode_to_curve for
+// Jubjub following RFC 9380: expand_message_xmd with BLAKE2b-512,
+// Elligator 2 on the birationally equivalent Montgomery curve, and
+// clear_cofactor with h_eff = 8.
+//
+// Unlike grouphash.FindGroupHash, the map never retries and hashing never
+// fails for a valid domain separation tag. The map runs in constant time:
+// selections use the masked fq.ConditionalSelect and fq.ConstantTimeEq,
+// and is_square and sqrt are fixed exponentiations (RFC 9380, appendix I),
+// so the sequence of field operations does not depend on the message.
+package hashtocurve
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/blake2b"
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fq"
+)
+
+// Suite IDs for the random oracle and nonuniform encodings. Applications
+// should use a domain separation tag of the form
+// "<application>-V<xx>-CS<yy>-with-" + SuiteRO (RFC 9380, section 3.1).
+const (
+	SuiteRO = "jubjub_XMD:BLAKE2b_ELL2_RO_"
+	SuiteNU = "jubjub_XMD:BLAKE2b_ELL2_NU_"
+)
+
+// L = ceil((ceil(log2(q)) + k) / 8) with k = 128
+const l = 48
+
+var (
+	ErrInvalidLength = jubjub.ErrInvalidLength
+
+	ErrInvalidDST    = errors.New("hashtocurve: domain separation tag must be between 1 and 255 bytes")
+	ErrExpandTooLong = errors.New("hashtocurve: requested too many bytes from expand_message_xmd")
+)
+
+// HashToCurve hashes msg to a point in the prime-order subgroup. Its
+// output is indistinguishable from a random oracle (the _RO_ suite).
+func HashToCurve(msg, dst []byte) (*extended.ExtendedPoint, error) {
+	u, err := HashToField(msg, dst, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	q0 := MapToCurve(u[0])
+	q1 := MapToCurve(u[1])
+
+	return q0.Add(q1).MulByCofactor(), nil
+}
+
+// EncodeToCurve hashes msg to a point in the prime-order subgroup. It is
+// cheaper than HashToCurve, but its output is not uniformly distributed
+// (the _NU_ suite).
+func EncodeToCurve(msg, dst []byte) (*extended.ExtendedPoint, error) {
+	u, err := HashToField(msg, dst, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return MapToCurve(u[0]).MulByCofactor(), nil
+}
+
+// HashToField hashes msg to count elements of Fq. It returns
+// ErrInvalidLength if count is negative or count*L overflows an int.
+func HashToField(msg, dst []byte, count int) ([]*fq.Fq, error) {
+	lenInBytes := count * l
+	if count < 0 || lenInBytes/l != count {
+		return nil, ErrInvalidLength
+	}
+
+	uniformBytes, err := expandMessageXMD(newBlake2b, msg, dst, lenInBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	u := make([]*fq.Fq, count)
+	for i := range u {
+		tv := uniformBytes[i*l : (i+1)*l]
+
+		// tv is big-endian, FromBytesWide expects 64 little-endian bytes
+		wide := make([]byte, 64)
+		for j := range tv {
+			wide[j] = tv[l-1-j]
+		}
+		u[i], _ = fq.FromBytesWide(wide)
+	}
+	return u, nil
+}
+
+// MapToCurve maps a field element to a point on Jubjub. The point is not
+// necessarily in the prime-order subgroup.
+func MapToCurve(u *fq.Fq) *extended.ExtendedPoint {
+	s, t := elligator2(u)
+	return montgomeryToEdwards(s, t)
+}
+
+// elligator2 maps u to a point (s, t) on K.t^2 = s^3 + J.s^2 + s
+// (RFC 9380, section 6.7.1)
+func elligator2(u *fq.Fq) (*fq.Fq, *fq.Fq) {
+	one := fq.One()
+
+	x1 := minusJDivK.Mul(one.Add(z.Mul(u.Square())).Inverse())
+	x1 = fq.ConditionalSelect(x1, &minusJDivK, isZero(x1))
+	gx1 := g(x1)
+
+	x2 := x1.Neg().Sub(&jDivK)
+	gx2 := g(x2)
+
+	e := isSquare(gx1)
+	x := fq.ConditionalSelect(x2, x1, e)
+	y := fq.ConditionalSelect(gx2, gx1, e).Sqrt()
+
+	// sgn0(y) must be 1 if gx1 is square, and 0 otherwise
+	y = fq.ConditionalSelect(y, y.Neg(), sgn0(y)^e)
+
+	return x.Mul(&k), y.Mul(&k)
+}
+
+// g(x) = x^3 + (J/K).x^2 + x/K^2
+func g(x *fq.Fq) *fq.Fq {
+	return x.Add(&jDivK).Mul(x).Add(&invKSqr).Mul(x)
+}
+
+// montgomeryToEdwards applies the rational map (s, t) -> (s/t, (s-1)/(s+1)),
+// sending the exceptional points t = 0 and s = -1 to the identity
+// (RFC 9380, appendix D.1)
+func montgomeryToEdwards(s, t *fq.Fq) *extended.ExtendedPoint {
+	one := fq.One()
+	sPlusOne := s.Add(one)
+
+	den := t.Mul(sPlusOne)
+	inv := den.Inverse()
+
+	u := s.Mul(sPlusOne).Mul(inv)
+	v := s.Sub(one).Mul(t).Mul(inv)
+
+	exceptional := isZero(den)
+	u = fq.ConditionalSelect(u, fq.Zero(), exceptional)
+	v = fq.ConditionalSelect(v, one, exceptional)
+
+	return extended.FromRawUnchecked(u, v)
+}
+
+// expandMessageXMD implements expand_message_xmd (RFC 9380, section 5.3.1)
+func expandMessageXMD(newHash func() hash.Hash, msg, dst []byte, lenInBytes int) ([]byte, error) {
+	if len(dst) == 0 || len(dst) > 255 {
+		return nil, ErrInvalidDST
+	}
+
+	h := newHash()
+	bInBytes := h.Size()
+	sInBytes := h.BlockSize()
+
+	ell := (lenInBytes + bInBytes - 1) / bInBytes
+	if ell > 255 || lenInBytes > 65535 {
+		return nil, ErrExpandTooLong
+	}
+
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	h.Write(make([]byte, sInBytes))
+	h.Write(msg)
+	h.Write([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+
+	uniformBytes := append([]byte{}, bi...)
+	for i := 2; i <= ell; i++ {
+		tmp := make([]byte, bInBytes)
+		for j := range tmp {
+			tmp[j] = b0[j] ^ bi[j]
+		}
+
+		h.Reset()
+		h.Write(tmp)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+
+		uniformBytes = append(uniformBytes, bi...)
+	}
+
+	return uniformBytes[:lenInBytes], nil
+}
+
+func newBlake2b() hash.Hash {
+	// New512 only fails for keys longer than 64 bytes
+	h, _ := blake2b.New512(nil)
+	return h
+}
+
+func isZero(f *fq.Fq) int {
+	return f.ConstantTimeEq(fq.Zero())
+}
+
+// isSquare returns 1 if f is zero or a quadratic residue
+func isSquare(f *fq.Fq) int {
+	return f.IsSquare()
+}
+
+func sgn0(f *fq.Fq) int {
+	return int(f.Bytes()[0] & 1)
+}
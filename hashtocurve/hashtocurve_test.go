@@ -0,0 +1,181 @@
+package hashtocurve
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/mechanizm/jubjub/fq"
+)
+
+// expand_message_xmd test vectors from RFC 9380, appendix K.1
+func TestExpandMessageXMDSHA256(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	vectors := []struct {
+		msg, uniformBytes string
+	}{
+		{"", "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+		{"abc", "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"},
+	}
+
+	for _, v := range vectors {
+		got, err := expandMessageXMD(sha256.New, []byte(v.msg), dst, 0x20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != v.uniformBytes {
+			t.Errorf("expand_message_xmd(%q) = %x, want %s", v.msg, got, v.uniformBytes)
+		}
+	}
+}
+
+var messages = []string{
+	"",
+	"abc",
+	"abcdef0123456789",
+	"q128_" + strings.Repeat("q", 128),
+	"a512_" + strings.Repeat("a", 512),
+}
+
+// The BLAKE2b suites are not part of RFC 9380, so there are no published
+// vectors for them. The vectors of TestHashToCurve and TestEncodeToCurve
+// are regression vectors generated by this package; only the
+// expand_message_xmd vectors above are external.
+func TestHashToCurve(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-" + SuiteRO)
+	vectors := []struct {
+		p      string
+		u0, u1 string
+	}{
+		{
+			"c222527c90184d9280925c3dbdf28a2a33d6f99edb589fc7327466386790a2dd",
+			"676cc60b76d4d3d676b468ff131e4683e94e2720bee4f7be94497ae1c5227b35",
+			"559f88cdab548c2381579042e73c0115771676d59546c2954722cf24a74b377f",
+		},
+		{
+			"cf9bf53b45ce77a4a83f4e76c606bc988b6bfd1175d9a7d5722bcaf46099f00f",
+			"1ba5756c9c74e1fcb1f47430edff0b26940c9aa46eef9724e08c7c913e03e11f",
+			"257ab3867838a1c1b37e663bff5e35c4d5dce49f9786908cefc0d7ad6f144e3e",
+		},
+		{
+			"8106f625974874b486f05b2b8bef00be970974876313befee9db08fa2d3d8abe",
+			"0895218a1174adcf66882dad5606fb8cc03576902a203d0b03a280dadc19ecb2",
+			"67e1ffbb0e089c7c07b91895e34a4813ae1ce72fec00f671467b5106cf170630",
+		},
+		{
+			"285da327543c5811099c57002c5d0a4eea1830a84d26b1fdf7383d199831b086",
+			"42b4471aead3dc9513e0dd1ad463536430486d08f27d3bb5e885a949be9424e7",
+			"28d3b1f7ad1563b12f825b0a33b9c5053417db675b7d3d41df71ee9b55c5a883",
+		},
+		{
+			"ca070c3ea678093cf3cc8139cb87b2b473e564669b88e03a491bfe707cab9c37",
+			"0f09edc0b182fe95f0bb158e1cbf691cd59380528d17eaac909f88980b66fafa",
+			"10c4d3dee560beb27ed6d3d9135d6f2f3cbcd40f5d7e0ad055803a493042b165",
+		},
+	}
+
+	for i, v := range vectors {
+		msg := []byte(messages[i])
+
+		u, err := HashToField(msg, dst, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u[0].String() != v.u0 || u[1].String() != v.u1 {
+			t.Errorf("hash_to_field(%q) = (%s, %s), want (%s, %s)", messages[i], u[0], u[1], v.u0, v.u1)
+		}
+
+		p, err := HashToCurve(msg, dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(p.Bytes()); got != v.p {
+			t.Errorf("hash_to_curve(%q) = %s, want %s", messages[i], got, v.p)
+		}
+		if !p.IsTorsionFree() {
+			t.Errorf("hash_to_curve(%q) is not in the prime-order subgroup", messages[i])
+		}
+	}
+}
+
+func TestEncodeToCurve(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-" + SuiteNU)
+	vectors := []struct {
+		p, u string
+	}{
+		{"682f877d5e24638c5e9a707f17957b55c959514aef5e25622f2de6d16a67300b", "167bdd8805543ed12417ca4107aaf360eba6f9781d87c7eee8854670b4731135"},
+		{"0e783a3e02ff5df2517d1500babb6c51b11ccc3f9c6d3a627118bceedf9ad1c0", "09f85f586df900b2dbb1542ecd7ab7ff22edbf6e56dc1e20e2dc40465709dc6e"},
+		{"6c43755e704525d04e20174b80ea19bb5c6bc389b9d0225aae1824a268f16bcb", "1629b94c0919bd4a53fa0e4291c2d974b20cfbb89fb42b8df7d4769cfa937c9c"},
+		{"343343bfd3fa70f144f1e12140e6c720844507e14e10466ff4a11a9d8abbfe49", "10845bcabb6cf5fdcbc3b900c07ea4e7584d42d1841240014c5135085b655f93"},
+		{"d895071fdbd2904e28a9fc9ae95fc3f21e87efcbf1c9cc13968270be044223ad", "05a3758fcf31efec82515c8394d5bc71063de3538002fe93a5c8e576761a0e8c"},
+	}
+
+	for i, v := range vectors {
+		msg := []byte(messages[i])
+
+		u, err := HashToField(msg, dst, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u[0].String() != v.u {
+			t.Errorf("hash_to_field(%q) = %s, want %s", messages[i], u[0], v.u)
+		}
+
+		p, err := EncodeToCurve(msg, dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(p.Bytes()); got != v.p {
+			t.Errorf("encode_to_curve(%q) = %s, want %s", messages[i], got, v.p)
+		}
+	}
+}
+
+func TestMapToCurveIsOnCurve(t *testing.T) {
+	// u = 0 takes the x1 == 0 branch of Elligator 2
+	inputs := []*fq.Fq{fq.Zero(), fq.One(), fq.One().Neg(), z.Neg()}
+	for _, u := range inputs {
+		a := MapToCurve(u).ToAffine()
+
+		// -u^2 + v^2 = 1 + d.u^2.v^2
+		uu, vv := a.U().Square(), a.V().Square()
+		lhs := vv.Sub(uu)
+		rhs := fq.One().Add(fq.D().Mul(uu).Mul(vv))
+		if !lhs.Equal(rhs) {
+			t.Errorf("MapToCurve(%s) is not on the curve", u)
+		}
+	}
+}
+
+func TestInvalidDST(t *testing.T) {
+	if _, err := HashToCurve([]byte("abc"), nil); err != ErrInvalidDST {
+		t.Errorf("expected ErrInvalidDST, got %v", err)
+	}
+	if _, err := HashToCurve([]byte("abc"), make([]byte, 256)); err != ErrInvalidDST {
+		t.Errorf("expected ErrInvalidDST, got %v", err)
+	}
+}
+
+func TestExpandTooLong(t *testing.T) {
+	// BLAKE2b-512 caps expand_message_xmd at 255 * 64 bytes
+	if _, err := expandMessageXMD(newBlake2b, []byte("abc"), []byte(SuiteRO), 255*64+1); err != ErrExpandTooLong {
+		t.Errorf("expected ErrExpandTooLong, got %v", err)
+	}
+	if _, err := HashToField([]byte("abc"), []byte(SuiteRO), 255*64/l+1); err != ErrExpandTooLong {
+		t.Errorf("expected ErrExpandTooLong, got %v", err)
+	}
+}
+
+func TestHashToFieldInvalidCount(t *testing.T) {
+	maxInt := int(^uint(0) >> 1)
+	for _, count := range []int{-1, -maxInt, maxInt, maxInt/l + 1} {
+		if _, err := HashToField([]byte("abc"), []byte(SuiteRO), count); err != ErrInvalidLength {
+			t.Errorf("count %d: expected ErrInvalidLength, got %v", count, err)
+		}
+	}
+	u, err := HashToField([]byte("abc"), []byte(SuiteRO), 0)
+	if err != nil || len(u) != 0 {
+		t.Errorf("count 0: got %d elements, %v", len(u), err)
+	}
+}
@@ -0,0 +1,137 @@
+package homomorphicpedersencommit
+
+import (
+	"math/big"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/blake2s"
+	"github.com/mechanizm/jubjub/extended"
+)
+
+// Personalizations of the asset value bases. The issuer key and the
+// description are first hashed to a fixed-length digest, which is then
+// hashed to the curve, like ZIP-227 does on Pallas. These values are
+// specific to this package, and the resulting bases are not the ones of
+// ZIP-227.
+var (
+	assetDigestDomain = []byte("ZSA_Desc")
+	assetBaseDomain   = []byte("ZSA_base")
+)
+
+// Asset is an asset type, modeled on ZIP-226: an issuer key and a
+// description. The zero value is the native asset, whose value base is the
+// Sapling ValueCommitmentValueBase, so that its commitments are the ones
+// of Commit. Asset is comparable, so it can be used as a map key.
+type Asset struct {
+	issuer      affine.PointBytes
+	description string
+	issued      bool
+}
+
+// NativeAsset returns the native asset
+func NativeAsset() Asset {
+	return Asset{}
+}
+
+// NewAsset returns the asset that issuer issues with the given
+// description. Assets with the same description and different issuers
+// have unrelated value bases, so an issuer cannot create value of another
+// issuer's asset.
+func NewAsset(issuer affine.PointBytes, description []byte) Asset {
+	return Asset{issuer: issuer, description: string(description), issued: true}
+}
+
+// IsNative returns true if a is the native asset
+func (a Asset) IsNative() bool {
+	return !a.issued
+}
+
+// assetBase returns the value base of asset in affine Niels form,
+// deriving it on first use
+func (committer *HomomorphicPedersenCommitter) assetBase(asset Asset) (*extended.AffineNielsPoint, error) {
+	if asset.IsNative() {
+		return committer.vBase, nil
+	}
+
+	committer.mu.RLock()
+	base, ok := committer.assetBases[asset]
+	committer.mu.RUnlock()
+	if ok {
+		return base, nil
+	}
+
+	blake, err := blake2s.New256WithPersonalization(nil, assetDigestDomain)
+	if err != nil {
+		return nil, err
+	}
+	// The issuer key has a fixed length, so the digest input is
+	// unambiguous
+	blake.Write(asset.issuer[:])
+	blake.Write([]byte(asset.description))
+	p, err := committer.assetHasher.FindGroupHash(blake.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	base = extended.AffineNielsFromJubjubPoint(p)
+
+	committer.mu.Lock()
+	defer committer.mu.Unlock()
+	committer.assetBases[asset] = base
+	return base, nil
+}
+
+// AssetBase returns the value base of asset
+func (committer *HomomorphicPedersenCommitter) AssetBase(asset Asset) (*jubjub.JubjubPoint, error) {
+	base, err := committer.assetBase(asset)
+	if err != nil {
+		return nil, err
+	}
+	return extended.Identity().AddAffineNiels(base).ToJubjubPoint(committer.curve)
+}
+
+// CommitAsset commits to the value v of asset with the trapdoor rcv. v may
+// be negative.
+func (committer *HomomorphicPedersenCommitter) CommitAsset(asset Asset, v *big.Int, rcv *big.Int) (*jubjub.JubjubPoint, error) {
+	base, err := committer.assetBase(asset)
+	if err != nil {
+		return nil, err
+	}
+	vSide := base.Mul(committer.scalar(v))
+	rSide := committer.rBase.Mul(committer.scalar(rcv))
+
+	return vSide.Add(rSide).ToJubjubPoint(committer.curve)
+}
+
+// BindingVerificationKey returns the sum of the spend commitments minus
+// the sum of the output commitments minus, for every asset, its value
+// balance committed with a zero trapdoor. Commitments may be to any asset.
+func (committer *HomomorphicPedersenCommitter) BindingVerificationKey(spends, outputs []*jubjub.JubjubPoint, valueBalances map[Asset]*big.Int) (*jubjub.JubjubPoint, error) {
+	bvk := extended.Identity()
+	for _, cv := range spends {
+		bvk = bvk.Add(extended.FromJubjubPoint(cv))
+	}
+	for _, cv := range outputs {
+		bvk = bvk.Sub(extended.FromJubjubPoint(cv))
+	}
+	for asset, balance := range valueBalances {
+		base, err := committer.assetBase(asset)
+		if err != nil {
+			return nil, err
+		}
+		bvk = bvk.Sub(base.Mul(committer.scalar(balance)))
+	}
+	return bvk.ToJubjubPoint(committer.curve)
+}
+
+// CheckBalance returns true if the values of every asset balance, that is
+// if the binding verification key is [bsk] times the randomness base,
+// where bsk is the sum of the spend trapdoors minus the sum of the output
+// trapdoors
+func (committer *HomomorphicPedersenCommitter) CheckBalance(spends, outputs []*jubjub.JubjubPoint, valueBalances map[Asset]*big.Int, bsk *big.Int) (bool, error) {
+	bvk, err := committer.BindingVerificationKey(spends, outputs, valueBalances)
+	if err != nil {
+		return false, err
+	}
+	return extended.FromJubjubPoint(bvk).Equal(committer.rBase.Mul(committer.scalar(bsk))), nil
+}
@@ -0,0 +1,127 @@
+package homomorphicpedersencommit
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/generators"
+)
+
+// Issuer keys of the tests; any point encoding will do
+var (
+	issuer      = generators.SpendingKeyGenerator().PointBytes()
+	otherIssuer = generators.ProofGenerationKeyGenerator().PointBytes()
+)
+
+func TestNativeAsset(t *testing.T) {
+	committer, err := NewCommitter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, rcv := big.NewInt(3160994844294270608), big.NewInt(123456789)
+
+	want, err := committer.Commit(v, rcv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := committer.CommitAsset(NativeAsset(), v, rcv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestAssetBases(t *testing.T) {
+	committer, err := NewCommitter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	native, err := committer.AssetBase(NativeAsset())
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{native.String(): true}
+	for _, ik := range []affine.PointBytes{issuer, otherIssuer} {
+		for _, description := range []string{"", "gold", "silver", "gold\x00"} {
+			base, err := committer.AssetBase(NewAsset(ik, []byte(description)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !extended.FromJubjubPoint(base).IsPrimeOrder() {
+				t.Errorf("%s, %q: base is not of prime order", ik, description)
+			}
+			if seen[base.String()] {
+				t.Errorf("%s, %q: base is not distinct", ik, description)
+			}
+			seen[base.String()] = true
+		}
+	}
+
+	// a second committer derives the same bases concurrently
+	other, err := NewCommitter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			base, err := other.AssetBase(NewAsset(issuer, []byte("gold")))
+			if err != nil || !seen[base.String()] {
+				t.Errorf("got %v, %v", base, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCheckBalance(t *testing.T) {
+	committer, err := NewCommitter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gold := NewAsset(issuer, []byte("gold"))
+
+	commit := func(asset Asset, v, rcv int64) *jubjub.JubjubPoint {
+		cv, err := committer.CommitAsset(asset, big.NewInt(v), big.NewInt(rcv))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cv
+	}
+
+	// spend 10 native and 7 gold, output 4 native and 9 gold; 2 gold are
+	// issued in the transaction
+	spends := []*jubjub.JubjubPoint{commit(NativeAsset(), 10, 11), commit(gold, 7, 22)}
+	outputs := []*jubjub.JubjubPoint{commit(NativeAsset(), 4, 33), commit(gold, 9, 44)}
+	bsk := big.NewInt(11 + 22 - 33 - 44)
+
+	balances := map[Asset]*big.Int{NativeAsset(): big.NewInt(6), gold: big.NewInt(-2)}
+	ok, err := committer.CheckBalance(spends, outputs, balances, bsk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("balanced transaction rejected")
+	}
+
+	// the same amounts of another asset do not balance
+	for _, asset := range []Asset{NewAsset(issuer, []byte("silver")), NewAsset(otherIssuer, []byte("gold"))} {
+		balances = map[Asset]*big.Int{NativeAsset(): big.NewInt(6), asset: big.NewInt(-2)}
+		ok, err = committer.CheckBalance(spends, outputs, balances, bsk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Errorf("unbalanced transaction accepted with %q of %s", asset.description, asset.issuer)
+		}
+	}
+}
@@ -1,63 +1,68 @@
+// Package homomorphicpedersencommit computes homomorphic Pedersen value
+// commitments on Jubjub, with value bases for issued assets.
+//
+// The asset bases are a ZSA-style construction of this package only. Like
+// ZIP-227, they bind the issuer key and the asset description, but their
+// personalizations are not from any specification, so commitments to
+// issued assets do not interoperate with ZIP-227 or any Zcash deployment.
+// Commitments to the native asset are Sapling value commitments.
 package homomorphicpedersencommit
 
 import (
 	"math/big"
+	"sync"
 
 	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fr"
+	"github.com/mechanizm/jubjub/futil"
+	"github.com/mechanizm/jubjub/generators"
 	"github.com/mechanizm/jubjub/grouphash"
-	"github.com/mechanizm/jubjub/pedersenhash"
 )
 
-var domain = []byte("Zcash_PH")
-
 type HomomorphicPedersenCommitter struct {
-	curve          *jubjub.Jubjub
-	groupHasher    *grouphash.GroupHasher
-	pedersenHasher *pedersenhash.PedersenHasher
+	curve *jubjub.Jubjub
+
+	// value and randomness bases in affine Niels form
+	vBase, rBase *extended.AffineNielsPoint
+
+	// value bases of issued assets, derived on first use
+	assetHasher *grouphash.GroupHasher
+	mu          sync.RWMutex
+	assetBases  map[Asset]*extended.AffineNielsPoint
 }
 
 func NewCommitter() (*HomomorphicPedersenCommitter, error) {
 	j := jubjub.NewJubjub()
-	groupHasher, err := grouphash.NewGroupHasher([]byte("Zcash_cv"))
-	if err != nil {
-		return nil, err
-	}
-
-	pedersenHasher, err := pedersenhash.NewPedersenHasher()
+	assetHasher, err := grouphash.NewGroupHasher(assetBaseDomain)
 	if err != nil {
 		return nil, err
 	}
 
 	return &HomomorphicPedersenCommitter{
-		curve:          j,
-		groupHasher:    groupHasher,
-		pedersenHasher: pedersenHasher,
+		curve:       j,
+		vBase:       generators.ValueCommitmentValueBase().ToAffineNiels(),
+		rBase:       generators.ValueCommitmentRandomnessBase().ToAffineNiels(),
+		assetHasher: assetHasher,
+		assetBases:  make(map[Asset]*extended.AffineNielsPoint),
 	}, nil
 }
 
 func (committer *HomomorphicPedersenCommitter) Commit(v *big.Int, rcv *big.Int) (*jubjub.JubjubPoint, error) {
-	vBase, err := committer.groupHasher.FindGroupHash([]byte("v"))
-	if err != nil {
-		return nil, err
-	}
-	vSide, err := committer.curve.ScalarMult(v, vBase)
-	if err != nil {
-		return nil, err
-	}
+	vSide := committer.vBase.Mul(committer.scalar(v))
+	rSide := committer.rBase.Mul(committer.scalar(rcv))
 
-	rBase, err := committer.groupHasher.FindGroupHash([]byte("r"))
-	if err != nil {
-		return nil, err
-	}
-	rSide, err := committer.curve.ScalarMult(rcv, rBase)
-	if err != nil {
-		return nil, err
-	}
+	return vSide.Add(rSide).ToJubjubPoint(committer.curve)
+}
 
-	ret, err := committer.curve.Add(vSide, rSide)
-	if err != nil {
-		return nil, err
-	}
+// scalar reduces s modulo r
+func (committer *HomomorphicPedersenCommitter) scalar(s *big.Int) *fr.Fr {
+	reduced := new(big.Int).Mod(s, committer.curve.JubjubS)
 
-	return ret, nil
+	byt := make([]byte, 32)
+	reduced.FillBytes(byt)
+	futil.Reverse(byt)
+	// byt is the canonical encoding of reduced, so FromBytes cannot fail
+	f, _ := fr.FromBytes(byt)
+	return f
 }
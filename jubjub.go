@@ -1,8 +1,6 @@
 package jubjub
 
 import (
-	"errors"
-	"fmt"
 	"math/big"
 )
 
@@ -68,7 +66,7 @@ func (point *JubjubPoint) VerifyOnCurve() error {
 	sumBits := sum.Bits()
 	for i := range sumBits {
 		if sumBits[i] != 0 {
-			return errors.New("not on curve")
+			return ErrNotOnCurve
 		}
 	}
 	return nil
@@ -87,18 +85,21 @@ func (curve *Jubjub) MulByCofactor(point *JubjubPoint) (*JubjubPoint, error) {
 			break
 		}
 	}
+	// Only the points of small order have u = 0 after clearing the cofactor
 	if allZeros {
-		return nil, errors.New("not on curve")
+		return nil, ErrSmallOrder
 	}
 
 	return retPoint, nil
 }
 
+// Point returns the point (x, y) if it is on the curve. x and y are
+// copied, so the caller may reuse them.
 func (curve *Jubjub) Point(x *big.Int, y *big.Int) (*JubjubPoint, error) {
 	point := &JubjubPoint{
 		curve: curve,
-		x:     x,
-		y:     y,
+		x:     new(big.Int).Set(x),
+		y:     new(big.Int).Set(y),
 	}
 
 	err := point.VerifyOnCurve()
@@ -111,7 +112,7 @@ func (curve *Jubjub) Point(x *big.Int, y *big.Int) (*JubjubPoint, error) {
 
 func (curve *Jubjub) GetForY(y *big.Int, shouldBeOdd bool) (*JubjubPoint, error) {
 	if cmp := y.Cmp(curve.BlsR); cmp == 1 || cmp == 0 {
-		return nil, fmt.Errorf("not in field")
+		return nil, ErrNonCanonical
 	}
 	// fmt.Printf("cmp: %d\n", y.Cmp(curve.BlsR))
 	ySqr := big.NewInt(0)
@@ -132,15 +133,13 @@ func (curve *Jubjub) GetForY(y *big.Int, shouldBeOdd bool) (*JubjubPoint, error)
 	rhs.Set(ySqrMinus1)
 	rhs.Mul(rhs, dPlus1Inv)
 	rhs.Mod(rhs, curve.BlsR)
-	rhs.ModSqrt(rhs, curve.BlsR)
-	if shouldBeOdd {
-		mod2Big := big.NewInt(0)
-		isOdd := mod2Big.Set(rhs).Mod(mod2Big, big.NewInt(2)).Uint64() == 1
-		if isOdd != shouldBeOdd {
-			rhs.Neg(rhs)
-			rhs.Mod(rhs, curve.BlsR)
-		}
-
+	if rhs.ModSqrt(rhs, curve.BlsR) == nil {
+		return nil, ErrNotOnCurve
+	}
+	// ModSqrt returns either root, so fix the parity in both cases
+	if isOdd := rhs.Bit(0) == 1; isOdd != shouldBeOdd {
+		rhs.Neg(rhs)
+		rhs.Mod(rhs, curve.BlsR)
 	}
 
 	point := &JubjubPoint{
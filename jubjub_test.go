@@ -0,0 +1,33 @@
+package jubjub
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestGetForYParity checks that the u-coordinate has the requested parity
+// for both values of shouldBeOdd. ModSqrt returns either root, so an even
+// request must also be corrected.
+func TestGetForYParity(t *testing.T) {
+	curve := NewJubjub()
+	found := 0
+	for y := int64(2); y < 64; y++ {
+		for _, shouldBeOdd := range []bool{false, true} {
+			p, err := curve.GetForY(big.NewInt(y), shouldBeOdd)
+			if err != nil {
+				// y is not the coordinate of a point
+				continue
+			}
+			found++
+			if isOdd := p.X().Bit(0) == 1; isOdd != shouldBeOdd {
+				t.Errorf("GetForY(%d, %v) returned u = %s", y, shouldBeOdd, p.X().Text(16))
+			}
+			if err := p.VerifyOnCurve(); err != nil {
+				t.Errorf("GetForY(%d, %v) is not on the curve", y, shouldBeOdd)
+			}
+		}
+	}
+	if found == 0 {
+		t.Errorf("no point found")
+	}
+}
@@ -0,0 +1,250 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/fq"
+)
+
+var (
+	ErrInvalidLength = jubjub.ErrInvalidLength
+	ErrNonCanonical  = jubjub.ErrNonCanonical
+
+	ErrTreeFull        = errors.New("merkle: note commitment tree is full")
+	ErrInvalidFrontier = errors.New("merkle: invalid frontier")
+)
+
+// Frontier is the right edge of an append-only note commitment tree: the
+// nodes needed to compute its root and to append to it. It holds the same
+// data as the IncrementalMerkleTree of zcashd: the last one or two leaves,
+// and the roots of the complete subtrees to their left. A Frontier must
+// not be used concurrently.
+type Frontier struct {
+	// left and right are the leaves of the last, possibly incomplete,
+	// pair. right is nil if the number of leaves is odd.
+	left, right *fq.Fq
+
+	// parents[i] is the root of the complete subtree of height i+1 left
+	// of the last pair, or nil if there is none
+	parents []*fq.Fq
+
+	size uint64
+
+	// root caches Root until the next Append
+	root *fq.Fq
+}
+
+// NewFrontier returns the frontier of an empty tree
+func NewFrontier() *Frontier {
+	return &Frontier{}
+}
+
+// Size returns the number of leaves in the tree
+func (f *Frontier) Size() uint64 {
+	return f.size
+}
+
+// Append adds a note commitment cmu as the next leaf. It returns
+// ErrTreeFull if the tree already has 2^Depth leaves.
+func (f *Frontier) Append(cmu *fq.Fq) error {
+	if f.size == 1<<Depth {
+		return ErrTreeFull
+	}
+
+	leaf := fq.Set(cmu)
+	switch {
+	case f.left == nil:
+		f.left = leaf
+	case f.right == nil:
+		f.right = leaf
+	default:
+		// The pair is complete: carry its root into the parents, like
+		// incrementing a binary counter
+		carry := merkleCRH(0, f.left, f.right)
+		f.left, f.right = leaf, nil
+
+		i := 0
+		for ; i < len(f.parents) && f.parents[i] != nil; i++ {
+			carry = merkleCRH(i+1, f.parents[i], carry)
+			f.parents[i] = nil
+		}
+		if i == len(f.parents) {
+			f.parents = append(f.parents, carry)
+		} else {
+			f.parents[i] = carry
+		}
+	}
+
+	f.size++
+	f.root = nil
+	return nil
+}
+
+// Root returns the root of the tree, in which the leaves after the
+// appended ones are Uncommitted
+func (f *Frontier) Root() *fq.Fq {
+	if f.root != nil {
+		return fq.Set(f.root)
+	}
+	if f.left == nil {
+		return EmptyRoot(Depth)
+	}
+
+	right := f.right
+	if right == nil {
+		right = EmptyRoot(0)
+	}
+	root := merkleCRH(0, f.left, right)
+	for i := 0; i < Depth-1; i++ {
+		if i < len(f.parents) && f.parents[i] != nil {
+			root = merkleCRH(i+1, f.parents[i], root)
+		} else {
+			root = merkleCRH(i+1, root, EmptyRoot(i+1))
+		}
+	}
+
+	f.root = root
+	return fq.Set(root)
+}
+
+// Bytes serializes f in the format of the IncrementalMerkleTree of
+// zcashd, as returned in the finalState of z_gettreestate:
+// Optional(left) || Optional(right) || CompactSize(n) || n Optional(parent),
+// where Optional(x) is 0x00 if x is missing and 0x01 || x otherwise.
+func (f *Frontier) Bytes() []byte {
+	var buf bytes.Buffer
+	writeOptional(&buf, f.left)
+	writeOptional(&buf, f.right)
+	// there are at most Depth-1 parents, so CompactSize is a single byte
+	buf.WriteByte(byte(len(f.parents)))
+	for _, p := range f.parents {
+		writeOptional(&buf, p)
+	}
+	return buf.Bytes()
+}
+
+// FrontierFromBytes decodes the output of Bytes. It rejects nodes that are
+// not canonical field elements and frontiers that no sequence of appends
+// to a tree of depth Depth produces.
+func FrontierFromBytes(byt []byte) (*Frontier, error) {
+	r := bytes.NewReader(byt)
+	f := &Frontier{}
+
+	var err error
+	if f.left, err = readOptional(r); err != nil {
+		return nil, err
+	}
+	if f.right, err = readOptional(r); err != nil {
+		return nil, err
+	}
+	n, err := readCompactSize(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > Depth-1 {
+		return nil, ErrInvalidFrontier
+	}
+	f.parents = make([]*fq.Fq, n)
+	for i := range f.parents {
+		if f.parents[i], err = readOptional(r); err != nil {
+			return nil, err
+		}
+	}
+	if r.Len() != 0 {
+		return nil, ErrInvalidLength
+	}
+
+	// Appends fill left before right, leave the last pair non-empty once
+	// there are parents and never end the parents with an empty node
+	if f.left == nil && (f.right != nil || n > 0) {
+		return nil, ErrInvalidFrontier
+	}
+	if n > 0 && f.parents[n-1] == nil {
+		return nil, ErrInvalidFrontier
+	}
+
+	if f.left != nil {
+		f.size++
+	}
+	if f.right != nil {
+		f.size++
+	}
+	for i, p := range f.parents {
+		if p != nil {
+			f.size += 2 << i
+		}
+	}
+	return f, nil
+}
+
+func writeOptional(buf *bytes.Buffer, node *fq.Fq) {
+	if node == nil {
+		buf.WriteByte(0)
+		return
+	}
+	buf.WriteByte(1)
+	buf.Write(node.Bytes())
+}
+
+func readOptional(r *bytes.Reader) (*fq.Fq, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, ErrInvalidLength
+	}
+	switch tag {
+	case 0:
+		return nil, nil
+	case 1:
+	default:
+		return nil, ErrInvalidFrontier
+	}
+
+	byt := make([]byte, 32)
+	if _, err := io.ReadFull(r, byt); err != nil {
+		return nil, ErrInvalidLength
+	}
+	node, err := fq.FromBytes(byt)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(node.Bytes(), byt) {
+		return nil, ErrNonCanonical
+	}
+	return node, nil
+}
+
+// readCompactSize reads the variable-length integer encoding of Bitcoin
+// and zcashd, rejecting encodings that are not minimal
+func readCompactSize(r *bytes.Reader) (uint64, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return 0, ErrInvalidLength
+	}
+
+	var size int
+	var min uint64
+	switch tag {
+	case 0xfd:
+		size, min = 2, 0xfd
+	case 0xfe:
+		size, min = 4, 0x10000
+	case 0xff:
+		size, min = 8, 0x100000000
+	default:
+		return uint64(tag), nil
+	}
+
+	byt := make([]byte, 8)
+	if _, err := io.ReadFull(r, byt[:size]); err != nil {
+		return 0, ErrInvalidLength
+	}
+	n := binary.LittleEndian.Uint64(byt)
+	if n < min {
+		return 0, ErrInvalidFrontier
+	}
+	return n, nil
+}
@@ -0,0 +1,288 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/mechanizm/jubjub/fq"
+)
+
+func testLeaf(t *testing.T, i int) *fq.Fq {
+	digest := sha512.Sum512([]byte(fmt.Sprintf("leaf %d", i)))
+	f, err := fq.FromBytesWide(digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// denseRoot computes the root of the tree with the given leaves level by
+// level, padding each level with empty roots
+func denseRoot(leaves []*fq.Fq) *fq.Fq {
+	nodes := leaves
+	for height := 0; height < Depth; height++ {
+		if len(nodes) == 0 {
+			return EmptyRoot(Depth)
+		}
+		if len(nodes)%2 == 1 {
+			nodes = append(nodes, EmptyRoot(height))
+		}
+		parents := make([]*fq.Fq, len(nodes)/2)
+		for i := range parents {
+			parents[i] = merkleCRH(height, nodes[2*i], nodes[2*i+1])
+		}
+		nodes = parents
+	}
+	return nodes[0]
+}
+
+func TestFrontierRoot(t *testing.T) {
+	f := NewFrontier()
+	if got := hex.EncodeToString(f.Root().Bytes()); got != emptyRootVectors[Depth] {
+		t.Errorf("empty tree root = %s", got)
+	}
+
+	var leaves []*fq.Fq
+	for n := 1; n <= 20; n++ {
+		leaf := testLeaf(t, n)
+		leaves = append(leaves, leaf)
+		if err := f.Append(leaf); err != nil {
+			t.Fatal(err)
+		}
+		if f.Size() != uint64(n) {
+			t.Errorf("size %d, want %d", f.Size(), n)
+		}
+		if got, want := f.Root(), denseRoot(leaves); !got.Equal(want) {
+			t.Errorf("%d leaves: root %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestFrontierBytes(t *testing.T) {
+	l1, l2, l3 := testLeaf(t, 1), testLeaf(t, 2), testLeaf(t, 3)
+	enc := func(f *fq.Fq) string { return hex.EncodeToString(f.Bytes()) }
+
+	f := NewFrontier()
+	vectors := []string{
+		// the sapling.finalState of z_gettreestate at the Sapling
+		// activation height, before any note was committed
+		"000000",
+		"01" + enc(l1) + "0000",
+		"01" + enc(l1) + "01" + enc(l2) + "00",
+		"01" + enc(l3) + "00" + "01" + "01" + enc(merkleCRH(0, l1, l2)),
+	}
+	for n, want := range vectors {
+		if n > 0 {
+			if err := f.Append(testLeaf(t, n)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := hex.EncodeToString(f.Bytes()); got != want {
+			t.Errorf("%d leaves: got %s, want %s", n, got, want)
+		}
+	}
+
+	// decoded frontiers continue the tree
+	for n := 4; n <= 12; n++ {
+		decoded, err := FrontierFromBytes(f.Bytes())
+		if err != nil {
+			t.Fatalf("%d leaves: %v", n, err)
+		}
+		if decoded.Size() != f.Size() || !bytes.Equal(decoded.Bytes(), f.Bytes()) {
+			t.Errorf("%d leaves: frontier does not round trip", n)
+		}
+
+		leaf := testLeaf(t, n)
+		if err := f.Append(leaf); err != nil {
+			t.Fatal(err)
+		}
+		if err := decoded.Append(leaf); err != nil {
+			t.Fatal(err)
+		}
+		if !decoded.Root().Equal(f.Root()) {
+			t.Errorf("%d leaves: decoded frontier gives another root", n)
+		}
+	}
+}
+
+// TestFrontierUncommittedLeaves checks non-empty trees against published
+// values. A tree whose leaves all equal Uncommitted has the root of the
+// empty tree, and its frontier holds the empty roots of librustzcash and
+// zcashd as ommers. Because both children of every node are equal, this
+// does not catch swapped children; TestFrontierRoot covers ordering.
+func TestFrontierUncommittedLeaves(t *testing.T) {
+	u := emptyRootVectors[0]
+	vectors := []string{
+		"01" + u + "0000",
+		"01" + u + "01" + u + "00",
+		"01" + u + "00" + "01" + "01" + emptyRootVectors[1],
+		"01" + u + "01" + u + "01" + "01" + emptyRootVectors[1],
+		"01" + u + "00" + "02" + "00" + "01" + emptyRootVectors[2],
+	}
+
+	f := NewFrontier()
+	for n := 1; n <= 16; n++ {
+		if err := f.Append(Uncommitted()); err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(f.Root().Bytes()); got != emptyRootVectors[Depth] {
+			t.Errorf("%d leaves: root %s, want %s", n, got, emptyRootVectors[Depth])
+		}
+		if n <= len(vectors) {
+			if got := hex.EncodeToString(f.Bytes()); got != vectors[n-1] {
+				t.Errorf("%d leaves: frontier %s, want %s", n, got, vectors[n-1])
+			}
+		}
+	}
+}
+
+// Trees of the note commitments cmu of the Sapling key components test
+// vectors, appended in order. The roots and frontiers were computed by a
+// separate implementation of MerkleCRH^Sapling and of the
+// IncrementalMerkleTree serialization; unlike the Uncommitted trees above,
+// they catch swapped children and misplaced ommers.
+var noteCommitmentTreeVectors = []struct {
+	cmu, root, frontier string
+}{
+	{
+		cmu:      "cb3cf9153270d57eb914c6c2bcc01850c9fed44fce0806278f083ef2dd076439",
+		root:     "5dd0bcb26499c098edcdb7de3751f98494ff08236b01738fd4ff09244ca13947",
+		frontier: "01cb3cf9153270d57eb914c6c2bcc01850c9fed44fce0806278f083ef2dd0764390000",
+	},
+	{
+		cmu:      "b57893500bfb85df2e8b01ac452f89e10e266bcfa31c31b29a53ae72cad46950",
+		root:     "1b49056c5dd0afb949fe7b19017a8ef70edfcc0dfbf2a3bcf2202612558ef270",
+		frontier: "01cb3cf9153270d57eb914c6c2bcc01850c9fed44fce0806278f083ef2dd07643901b57893500bfb85df2e8b01ac452f89e10e266bcfa31c31b29a53ae72cad4695000",
+	},
+	{
+		cmu:      "db85a70a98437f73167fc332d5b7b7408296661770b101b0aa87839f4e55f151",
+		root:     "754e3a9185b8c5c1bc44383ad82e130406407ade8a527b239a60e378d397bc56",
+		frontier: "01db85a70a98437f73167fc332d5b7b7408296661770b101b0aa87839f4e55f151000101f46a7ac672cafb4b1cc3a8e57fc278174575c5fa6317799b3622917662990f25",
+	},
+	{
+		cmu:      "e08ce482b3a8fb3b35ccdbe34337bd105d8839212e0d1644b9d55caa60d19b6c",
+		root:     "45c0c31204ffe8ed5784fbfb02499de95325a6281a80694bfc912708d6547669",
+		frontier: "01db85a70a98437f73167fc332d5b7b7408296661770b101b0aa87839f4e55f15101e08ce482b3a8fb3b35ccdbe34337bd105d8839212e0d1644b9d55caa60d19b6c0101f46a7ac672cafb4b1cc3a8e57fc278174575c5fa6317799b3622917662990f25",
+	},
+	{
+		cmu:      "bdc854bf3e7b00821f3b8b85238ccf1e6715bfe70b632d044b26fb2bc71b7f36",
+		root:     "665ba3102f37acf597ea7581d629ba706a6d0ebd41c18e98ef1db8907a694070",
+		frontier: "01bdc854bf3e7b00821f3b8b85238ccf1e6715bfe70b632d044b26fb2bc71b7f3600020001678e0ff29a5b3d46afac814aab878d86397bc1d044879e55b049eadf7a6ac071",
+	},
+	{
+		cmu:      "e8267d30ac11c100bc7a0fdf91f71d74c5bcf2e1ef95669044730169de1a5b4c",
+		root:     "fbce82aeff6bbc2285154eee7bd9b00f1152bcfbbae6cd6ac7303dbf3b64942c",
+		frontier: "01bdc854bf3e7b00821f3b8b85238ccf1e6715bfe70b632d044b26fb2bc71b7f3601e8267d30ac11c100bc7a0fdf91f71d74c5bcf2e1ef95669044730169de1a5b4c020001678e0ff29a5b3d46afac814aab878d86397bc1d044879e55b049eadf7a6ac071",
+	},
+	{
+		cmu:      "572ba20525b0ac4d6dc01ac2ea1090b6e0f2f4bf4ec4a0db5bbccb5b783a1e55",
+		root:     "441899949f0f1d76c9c469d56ef982e2a8d7075b93f3ca575734c3d1873d0900",
+		frontier: "01572ba20525b0ac4d6dc01ac2ea1090b6e0f2f4bf4ec4a0db5bbccb5b783a1e55000201b3a7d7b1533de06557c184847230aec5374d07f9ae65a9e780ff674f5203515601678e0ff29a5b3d46afac814aab878d86397bc1d044879e55b049eadf7a6ac071",
+	},
+	{
+		cmu:      "ab7fc566873ccde671f59827678560a006f82bb7adcd75223fa85936f78c2b23",
+		root:     "f030f2bfe28e98ca9f19d1f0c271a3292a4e03f7a96306b73071bf7d87fc7543",
+		frontier: "01572ba20525b0ac4d6dc01ac2ea1090b6e0f2f4bf4ec4a0db5bbccb5b783a1e5501ab7fc566873ccde671f59827678560a006f82bb7adcd75223fa85936f78c2b230201b3a7d7b1533de06557c184847230aec5374d07f9ae65a9e780ff674f5203515601678e0ff29a5b3d46afac814aab878d86397bc1d044879e55b049eadf7a6ac071",
+	},
+	{
+		cmu:      "7b48a8375d3ebd56bc649bb5b5242336c2a05a0803239b5b88fd92078fea4d04",
+		root:     "050d94466e979cfbc850acd8901e25a773427b3283978868b4d403045e118d6e",
+		frontier: "017b48a8375d3ebd56bc649bb5b5242336c2a05a0803239b5b88fd92078fea4d0400030000016f97f84eea56fb351816f2ce1161a89be1036fb3c7d9dea6b038374ad0840168",
+	},
+	{
+		cmu:      "d376a7bee8ce67f4efde56aa77cf64419b0e550abbcb8e2bcbda8b63e41deb37",
+		root:     "c19cd804477a68fc40f6e1122761ae5a798a452d93a924a959249f5f1b92c219",
+		frontier: "017b48a8375d3ebd56bc649bb5b5242336c2a05a0803239b5b88fd92078fea4d0401d376a7bee8ce67f4efde56aa77cf64419b0e550abbcb8e2bcbda8b63e41deb37030000016f97f84eea56fb351816f2ce1161a89be1036fb3c7d9dea6b038374ad0840168",
+	},
+}
+
+func TestFrontierNoteCommitments(t *testing.T) {
+	f := NewFrontier()
+	for n, v := range noteCommitmentTreeVectors {
+		byt, err := hex.DecodeString(v.cmu)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmu, err := fq.FromBytes(byt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Append(cmu); err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(f.Root().Bytes()); got != v.root {
+			t.Errorf("%d leaves: root %s, want %s", n+1, got, v.root)
+		}
+		if got := hex.EncodeToString(f.Bytes()); got != v.frontier {
+			t.Errorf("%d leaves: frontier %s, want %s", n+1, got, v.frontier)
+		}
+
+		byt, err = hex.DecodeString(v.frontier)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := FrontierFromBytes(byt)
+		if err != nil {
+			t.Fatalf("%d leaves: %v", n+1, err)
+		}
+		if hex.EncodeToString(decoded.Root().Bytes()) != v.root {
+			t.Errorf("%d leaves: decoded frontier gives another root", n+1)
+		}
+	}
+}
+
+func TestFrontierFromBytesErrors(t *testing.T) {
+	leaf := "01" + hex.EncodeToString(testLeaf(t, 1).Bytes())
+	modulus := "0101000000fffffffffe5bfeff02a4bd5305d8a10908d83933487d9d2953a7ed73"
+
+	for _, tc := range []struct {
+		name string
+		hex  string
+		err  error
+	}{
+		{"empty", "", ErrInvalidLength},
+		{"truncated leaf", leaf[:40], ErrInvalidLength},
+		{"trailing bytes", "00000000", ErrInvalidLength},
+		{"invalid tag", "020000", ErrInvalidFrontier},
+		{"non-canonical leaf", modulus + "0000", ErrNonCanonical},
+		{"right without left", "00" + leaf + "00", ErrInvalidFrontier},
+		{"parents without leaves", "000001" + leaf, ErrInvalidFrontier},
+		{"empty last parent", leaf + "000100", ErrInvalidFrontier},
+		{"non-minimal size", leaf + "00fd0100" + leaf, ErrInvalidFrontier},
+		{"too many parents", leaf + "0020", ErrInvalidFrontier},
+	} {
+		byt, err := hex.DecodeString(tc.hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := FrontierFromBytes(byt); err != tc.err {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.err)
+		}
+	}
+}
+
+func TestFrontierFull(t *testing.T) {
+	leaf := "01" + hex.EncodeToString(testLeaf(t, 1).Bytes())
+	full := leaf + leaf + "1f"
+	for i := 0; i < Depth-1; i++ {
+		full += leaf
+	}
+	byt, err := hex.DecodeString(full)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := FrontierFromBytes(byt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Size() != 1<<Depth {
+		t.Errorf("size %d, want 2^%d", f.Size(), Depth)
+	}
+	if err := f.Append(testLeaf(t, 2)); err != ErrTreeFull {
+		t.Errorf("expected ErrTreeFull, got %v", err)
+	}
+}
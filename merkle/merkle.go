@@ -0,0 +1,70 @@
+// Package merkle implements the Sapling note commitment tree, a Merkle
+// tree of depth 32 over the u-coordinates of note commitments, hashed with
+// the Pedersen hash.
+package merkle
+
+import (
+	"sync"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/fq"
+	"github.com/mechanizm/jubjub/pedersenhash"
+)
+
+// Depth is the depth of the Sapling note commitment tree
+const Depth = 32
+
+var ErrInvalidDepth = jubjub.ErrInvalidDepth
+
+// Uncommitted returns the value of the leaves that hold no note
+// commitment, 1
+func Uncommitted() *fq.Fq {
+	return fq.One()
+}
+
+// MerkleCRH hashes two nodes of the given layer, counted from the leaves,
+// into their parent. It is the Pedersen hash with personalization
+// MerkleTree(layer) of the 255 low bits of left followed by the 255 low
+// bits of right. It returns ErrInvalidDepth unless 0 <= layer < Depth.
+func MerkleCRH(layer int, left, right *fq.Fq) (*fq.Fq, error) {
+	if layer < 0 || layer >= Depth {
+		return nil, ErrInvalidDepth
+	}
+	return merkleCRH(layer, left, right), nil
+}
+
+// merkleCRH is MerkleCRH for a layer known to be valid
+func merkleCRH(layer int, left, right *fq.Fq) *fq.Fq {
+	// layer < Depth < 63, so MerkleTree cannot fail
+	personalization, _ := pedersenhash.MerkleTree(layer)
+	s := pedersenhash.NewStream(personalization)
+	for _, node := range []*fq.Fq{left, right} {
+		bits := make([]bool, 255)
+		byt := node.Bytes()
+		for i := range bits {
+			bits[i] = (byt[i/8]>>(i%8))&1 == 1
+		}
+		// 510 bits are always accepted
+		s.WriteBits(bits)
+	}
+	h, _ := s.Sum()
+	return h
+}
+
+var (
+	emptyRootsOnce sync.Once
+	emptyRoots     [Depth + 1]*fq.Fq
+)
+
+// EmptyRoot returns the root of an empty subtree of the given height: the
+// leaf Uncommitted for height 0 and the root of the empty tree for height
+// Depth. It panics if height is not between 0 and Depth.
+func EmptyRoot(height int) *fq.Fq {
+	emptyRootsOnce.Do(func() {
+		emptyRoots[0] = Uncommitted()
+		for i := 0; i < Depth; i++ {
+			emptyRoots[i+1] = merkleCRH(i, emptyRoots[i], emptyRoots[i])
+		}
+	})
+	return fq.Set(emptyRoots[height])
+}
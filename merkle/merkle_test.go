@@ -0,0 +1,53 @@
+package merkle
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Roots of the empty Sapling note commitment tree, as in librustzcash and
+// zcashd
+var emptyRootVectors = map[int]string{
+	0:  "0100000000000000000000000000000000000000000000000000000000000000",
+	1:  "817de36ab2d57feb077634bca77819c8e0bd298c04f6fed0e6a83cc1356ca155",
+	2:  "ffe9fc03f18b176c998806439ff0bb8ad193afdb27b2ccbc88856916dd804e34",
+	3:  "d8283386ef2ef07ebdbb4383c12a739a953a4d6e0d6fb1139a4036d693bfbb6c",
+	4:  "e110de65c907b9dea4ae0bd83a4b0a51bea175646a64c12b4c9f931b2cb31b49",
+	16: "1ea6675f9551eeb9dfaaa9247bc9858270d3d3a4c5afa7177a984d5ed1be2451",
+	31: "b2eed031d4d6a4f02a097f80b54cc1541d4163c6b6f5971f88b6e41d35c53814",
+	32: "fbc2f4300c01f0b7820d00e3347c8da4ee614674376cbc45359daa54f9b5493e",
+}
+
+func TestEmptyRoots(t *testing.T) {
+	for height, want := range emptyRootVectors {
+		if got := hex.EncodeToString(EmptyRoot(height).Bytes()); got != want {
+			t.Errorf("empty root of height %d = %s, want %s", height, got, want)
+		}
+	}
+	for height := 0; height < Depth; height++ {
+		parent, err := MerkleCRH(height, EmptyRoot(height), EmptyRoot(height))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !parent.Equal(EmptyRoot(height + 1)) {
+			t.Errorf("empty root of height %d is not the hash of its children", height+1)
+		}
+	}
+}
+
+func TestMerkleCRHInvalidLayer(t *testing.T) {
+	for _, layer := range []int{-1, Depth, 63, 64} {
+		if _, err := MerkleCRH(layer, Uncommitted(), Uncommitted()); err != ErrInvalidDepth {
+			t.Errorf("layer %d: expected ErrInvalidDepth, got %v", layer, err)
+		}
+	}
+}
+
+func TestEmptyRootPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	EmptyRoot(Depth + 1)
+}
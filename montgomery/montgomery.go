@@ -0,0 +1,127 @@
+// Package montgomery implements x-only scalar multiplication on the
+// Montgomery form of Jubjub, y^2 = x^3 + A.x^2 + x with A = 40962,
+// in the style of X25519 (RFC 7748).
+//
+// Following RFC 7748, this package calls the Montgomery x-coordinate u.
+// It is not the u-coordinate of a Jubjub point (u, v) in Sapling
+// notation: the Montgomery u of that point is (1 + v)/(1 - v), which U
+// computes. Passing a Sapling u to ScalarMult gives a meaningless result.
+package montgomery
+
+import (
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/affine"
+	"github.com/mechanizm/jubjub/fq"
+)
+
+const (
+	// ScalarSize is the size of scalars in bytes
+	ScalarSize = 32
+
+	// PointSize is the size of u-coordinates in bytes
+	PointSize = 32
+)
+
+var (
+	ErrInvalidLength = jubjub.ErrInvalidLength
+	ErrSmallOrder    = jubjub.ErrSmallOrder
+)
+
+// a24 = (A - 2)/4 = 10240
+var a24 = fq.Fq{
+	0x0000_5853_ffff_a7ac,
+	0x5565_2704_90ea_2854,
+	0x4ec2_5a0d_34a3_4977,
+	0x4480_8268_3542_12b3,
+}
+
+// ScalarMult returns the Montgomery u-coordinate of [scalar]P, where P
+// is a point with Montgomery u-coordinate u, both given as 32 byte
+// little-endian strings.
+//
+// As in X25519 the scalar is clamped: its three low bits are cleared, so
+// the result never depends on the component of P in the 8-torsion
+// subgroup, nor in the 4-torsion subgroup of the quadratic twist. u does
+// not need to be on the curve, and non-canonical values are reduced
+// modulo q. ErrSmallOrder is returned if the result is the identity or
+// the point of order 2, which both have u-coordinate zero.
+func ScalarMult(scalar, u []byte) ([]byte, error) {
+	if len(scalar) != ScalarSize || len(u) != PointSize {
+		return nil, ErrInvalidLength
+	}
+
+	k := make([]byte, ScalarSize)
+	copy(k, scalar)
+	k[0] &= 0b1111_1000
+
+	x, err := fq.FromBytes(u)
+	if err != nil {
+		return nil, err
+	}
+
+	res := ladder(k, x)
+	if res.ConstantTimeEq(fq.Zero()) == 1 {
+		return nil, ErrSmallOrder
+	}
+	return res.Bytes(), nil
+}
+
+// U returns the Montgomery u-coordinate (1 + v)/(1 - v) of a, as
+// expected by ScalarMult
+func U(a *affine.AffinePoint) []byte {
+	return a.ToMontgomery().X.Bytes()
+}
+
+// ladder runs the Montgomery ladder over all bits of the little-endian
+// scalar k. The number of iterations and the sequence of field operations
+// do not depend on k or u: cswap masks instead of branching, and the
+// final fq.Inverse is a fixed addition chain. The identity is returned as
+// zero.
+func ladder(k []byte, u *fq.Fq) *fq.Fq {
+	x1 := fq.Set(u)
+	x2, z2 := fq.One(), fq.Zero()
+	x3, z3 := fq.Set(u), fq.One()
+
+	swap := 0
+	for t := 8*len(k) - 1; t >= 0; t-- {
+		kt := int(k[t/8]>>(t%8)) & 1
+
+		swap ^= kt
+		x2, x3 = cswap(x2, x3, swap)
+		z2, z3 = cswap(z2, z3, swap)
+		swap = kt
+
+		a := x2.Add(z2)
+		aa := a.Square()
+		b := x2.Sub(z2)
+		bb := b.Square()
+		e := aa.Sub(bb)
+		c := x3.Add(z3)
+		d := x3.Sub(z3)
+		da := d.Mul(a)
+		cb := c.Mul(b)
+
+		x3 = da.Add(cb).Square()
+		z3 = x1.Mul(da.Sub(cb).Square())
+		x2 = aa.Mul(bb)
+		z2 = e.Mul(aa.Add(a24.Mul(e)))
+	}
+	x2, _ = cswap(x2, x3, swap)
+	z2, _ = cswap(z2, z3, swap)
+
+	return x2.Mul(z2.Inverse())
+}
+
+// cswap returns (b, a) if choice is 1 and (a, b) if it is 0, as copies,
+// by XORing both with the masked difference of their limbs
+func cswap(a, b *fq.Fq, choice int) (*fq.Fq, *fq.Fq) {
+	mask := -uint64(choice)
+
+	x, y := fq.Set(a), fq.Set(b)
+	for i := range x {
+		t := mask & (x[i] ^ y[i])
+		x[i] ^= t
+		y[i] ^= t
+	}
+	return x, y
+}
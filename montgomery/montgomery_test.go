@@ -0,0 +1,206 @@
+package montgomery
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fq"
+	"github.com/mechanizm/jubjub/fr"
+	"github.com/mechanizm/jubjub/futil"
+)
+
+// Twist security
+//
+// Jubjub has order 8.r, where r is the 252-bit prime subgroup order. Its
+// quadratic twist has order 2.(q + 1) - 8.r = 4.r', where r' is a
+// 253-bit prime. An x-only ladder accepts any u in Fq: when
+// u^3 + A.u^2 + u is not a square, u is the x-coordinate of a point on
+// the twist rather than on Jubjub.
+//
+// An attacker sending such a u learns [k]U on the twist, which can only
+// reveal k modulo the order of U. Clamping clears the low three bits of
+// k, so the components of order 2 and 4 are killed and the remaining
+// component has order r', for which discrete logs are as hard as on
+// Jubjub itself. The tests below check each of these facts.
+
+var (
+	jubjubOrder, _ = new(big.Int).SetString("e7db4ea6533afa906673b0101343b00a6682093ccc81082d0970e5ed6f72cb7", 16)
+	fieldOrder, _  = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+	twistPrime, _  = new(big.Int).SetString("1cfb69d4ca675f520cce7602026876015d0e90d9e66f0cf9ded1e341d211a693", 16)
+)
+
+func leBytes(n *big.Int) []byte {
+	byt := make([]byte, 32)
+	n.FillBytes(byt)
+	futil.Reverse(byt)
+	return byt
+}
+
+func basePoint(t *testing.T) *extended.ExtendedPoint {
+	byt, err := hex.DecodeString("7d09bb9aa97704719c33d1f6e7ed7e8d6c0edad0a02f7af82ab77ebc104f5f1e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := extended.FromBytes(byt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestTwistOrder(t *testing.T) {
+	// 2.(q + 1) = 8.r + 4.r'
+	lhs := new(big.Int).Add(fieldOrder, big.NewInt(1))
+	lhs.Lsh(lhs, 1)
+	rhs := new(big.Int).Add(new(big.Int).Lsh(jubjubOrder, 3), new(big.Int).Lsh(twistPrime, 2))
+	if lhs.Cmp(rhs) != 0 {
+		t.Fatalf("2.(q + 1) != 8.r + 4.r'")
+	}
+	if !twistPrime.ProbablyPrime(32) {
+		t.Fatalf("r' is not prime")
+	}
+	if twistPrime.BitLen() != 253 {
+		t.Fatalf("r' has %d bits", twistPrime.BitLen())
+	}
+}
+
+// montgomeryA = A = 40962
+var montgomeryA = fq.Fq{
+	0x0001_6155_fffe_9eaa,
+	0x5f22_c400_43b2_7956,
+	0x07ae_5804_98c2_15bd,
+	0x5a70_1dad_db57_5b1c,
+}
+
+// twistPoint returns the u-coordinate of a point on the twist
+func twistPoint(t *testing.T) *fq.Fq {
+	u := fq.One()
+	for i := 0; i < 64; i++ {
+		u = u.Add(fq.One())
+		rhs := u.Add(&montgomeryA).Mul(u).Add(fq.One()).Mul(u)
+		if rhs.LegendreSymbolVarTime().Equal(fq.One().Neg()) {
+			return u
+		}
+	}
+	t.Fatal("no twist point found")
+	return nil
+}
+
+func TestTwistSecurity(t *testing.T) {
+	u := twistPoint(t)
+
+	// [4.r']U is the identity on the twist, while [r']U is not: it is
+	// a point of order 2 or 4
+	order := new(big.Int).Lsh(twistPrime, 2)
+	if !ladder(leBytes(order), u).Equal(fq.Zero()) {
+		t.Fatalf("[4.r']U is not the identity")
+	}
+	small := ladder(leBytes(twistPrime), u)
+
+	// Clamping kills the small component, so the low order point
+	// reveals nothing about the scalar
+	for i := 0; i < 8; i++ {
+		k := sha512.Sum512([]byte{byte(i)})
+		if _, err := ScalarMult(k[:32], small.Bytes()); err != ErrSmallOrder {
+			t.Errorf("ScalarMult on a small order twist point returned %v", err)
+		}
+	}
+
+	// ... and the result for U only depends on k modulo r'
+	k := sha512.Sum512([]byte("k"))
+	k1 := new(big.Int).SetBytes(k[:31])
+	k1.Lsh(k1, 3)
+	k2 := new(big.Int).Add(k1, new(big.Int).Lsh(twistPrime, 3))
+	if !ladder(leBytes(k1), u).Equal(ladder(leBytes(k2), u)) {
+		t.Errorf("[k]U != [k + 8.r']U")
+	}
+}
+
+func TestCrossCheckExtended(t *testing.T) {
+	// p has prime order
+	p := basePoint(t).MulByCofactor()
+
+	for i := 0; i < 16; i++ {
+		k := sha512.Sum512([]byte{byte(i)})
+		scalar := k[:32]
+
+		got, err := ScalarMult(scalar, U(p.ToAffine()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		clamped := append([]byte{}, scalar...)
+		clamped[0] &= 0b1111_1000
+		s, err := fr.FromBytes(clamped)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := U(p.Mul(s).ToAffine())
+
+		if !bytes.Equal(got, want) {
+			t.Errorf("ScalarMult(%x) = %x, want %x", scalar, got, want)
+		}
+	}
+}
+
+func TestTorsionIsIgnored(t *testing.T) {
+	b := basePoint(t)
+	p := b.MulByCofactor()
+
+	// (0, -1) has order 2 and [r]b = [r-1]b + b is in the 8-torsion
+	// subgroup
+	torsion := []*extended.ExtendedPoint{
+		extended.FromRawUnchecked(fq.Zero(), fq.One().Neg()),
+		b.Mul(fr.One().Neg()).Add(b),
+	}
+
+	k := sha512.Sum512([]byte("torsion"))
+	want, err := ScalarMult(k[:32], U(p.ToAffine()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tp := range torsion {
+		got, err := ScalarMult(k[:32], U(p.Add(tp).ToAffine()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("torsion component changed the result")
+		}
+
+		if _, err := ScalarMult(k[:32], U(tp.ToAffine())); err != ErrSmallOrder {
+			t.Errorf("expected ErrSmallOrder for a torsion point, got %v", err)
+		}
+	}
+}
+
+func TestInvalidInputs(t *testing.T) {
+	if _, err := ScalarMult(make([]byte, 31), make([]byte, 32)); err != ErrInvalidLength {
+		t.Errorf("expected ErrInvalidLength, got %v", err)
+	}
+	if _, err := ScalarMult(make([]byte, 32), make([]byte, 33)); err != ErrInvalidLength {
+		t.Errorf("expected ErrInvalidLength, got %v", err)
+	}
+	// u = 0 is the point of order 2
+	k := sha512.Sum512(nil)
+	if _, err := ScalarMult(k[:32], make([]byte, 32)); err != ErrSmallOrder {
+		t.Errorf("expected ErrSmallOrder, got %v", err)
+	}
+}
+
+func TestCswap(t *testing.T) {
+	a, b := fq.One(), fq.D()
+	for choice, want := range [][2]*fq.Fq{{fq.One(), fq.D()}, {fq.D(), fq.One()}} {
+		x, y := cswap(a, b, choice)
+		if !x.Equal(want[0]) || !y.Equal(want[1]) {
+			t.Errorf("cswap(1, d, %d) = %s, %s", choice, x, y)
+		}
+	}
+	if !a.Equal(fq.One()) || !b.Equal(fq.D()) {
+		t.Errorf("cswap modified its operands")
+	}
+}
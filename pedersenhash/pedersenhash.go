@@ -2,98 +2,140 @@ package pedersenhash
 
 import (
 	"encoding/binary"
-	"math/big"
+	"errors"
+	"sync"
 
 	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/generators"
 	"github.com/mechanizm/jubjub/grouphash"
 )
 
+// DefaultMaxInputBits is the longest input accepted by NewPedersenHasher.
+// It is far above the 582 bits that Sapling note commitments hash.
+const DefaultMaxInputBits = 1 << 16
+
+var (
+	ErrInvalidLength = jubjub.ErrInvalidLength
+	ErrSmallOrder    = jubjub.ErrSmallOrder
+	ErrNotInSubgroup = jubjub.ErrNotInSubgroup
+	ErrInvalidDepth  = jubjub.ErrInvalidDepth
+
+	ErrInputTooLong              = errors.New("pedersenhash: input is longer than the maximum length")
+	ErrInvalidChunksPerGenerator = errors.New("pedersenhash: chunks per generator must be between 1 and 63")
+)
+
 func divCeil(x, y int) int {
 	return (x + y - 1) / y
 }
 
 type PedersenHasher struct {
-	curve      *jubjub.Jubjub
-	generators []*jubjub.JubjubPoint
+	curve *jubjub.Jubjub
+
+	// window tables of the segment generators. The Sapling generators
+	// are fixed, the following ones are derived with grouphash when an
+	// input first needs them.
+	mu          sync.RWMutex
+	tables      []segmentTable
+	groupHasher *grouphash.GroupHasher
 
 	chunksPerGenerator int
+	maxInputBits       int
 }
 
 func NewPedersenHasher() (*PedersenHasher, error) {
+	return NewPedersenHasherWithMaxInputBits(DefaultMaxInputBits)
+}
+
+// NewPedersenHasherWithMaxInputBits returns a Sapling hasher that
+// rejects inputs longer than maxInputBits bits, not counting the
+// personalization, with ErrInputTooLong
+func NewPedersenHasherWithMaxInputBits(maxInputBits int) (*PedersenHasher, error) {
+	return NewPedersenHasherWithParams([]byte("Zcash_PH"), 63, generators.PedersenHashGenerators(), maxInputBits)
+}
+
+// NewPedersenHasherWithParams returns a hasher for a separate domain. The
+// generator of segment i is GroupHash^J(r)*(personalization, LE32(i)),
+// unless gens is long enough to provide it, and each segment holds
+// chunksPerGenerator 3-bit chunks. The hash is collision resistant only
+// if chunksPerGenerator is at most 63 and the generators are independent.
+// personalization is used as a BLAKE2s personalization of at most 8
+// bytes.
+func NewPedersenHasherWithParams(personalization []byte, chunksPerGenerator int, gens []*extended.ExtendedPoint, maxInputBits int) (*PedersenHasher, error) {
+	if len(personalization) > 8 || maxInputBits < 0 {
+		return nil, ErrInvalidLength
+	}
+	if chunksPerGenerator < 1 || chunksPerGenerator > 63 {
+		return nil, ErrInvalidChunksPerGenerator
+	}
+
 	j := jubjub.NewJubjub()
 
-	hasher, err := grouphash.NewGroupHasher([]byte("Zcash_PH"))
+	groupHasher, err := grouphash.NewGroupHasher(personalization)
 	if err != nil {
 		return nil, err
 	}
 
-	generators := []*jubjub.JubjubPoint{}
-	for i := 0; i < 5; i++ {
-		msg := make([]byte, 4)
-		binary.LittleEndian.PutUint32(msg, uint32(i))
-
-		p, err := hasher.FindGroupHash(msg)
-		if err != nil {
-			return nil, err
+	tables := []segmentTable{}
+	for _, g := range gens {
+		if !g.IsTorsionFree() {
+			return nil, ErrNotInSubgroup
 		}
-
-		if err != nil {
-			return nil, err
+		if g.IsIdentity() {
+			return nil, ErrSmallOrder
 		}
-		// fmt.Printf("generator: %v\n", p)
-		generators = append(generators, p)
+		tables = append(tables, newSegmentTable(g, chunksPerGenerator))
 	}
 
 	return &PedersenHasher{
 		curve:              j,
-		generators:         generators,
-		chunksPerGenerator: 63,
+		tables:             tables,
+		groupHasher:        groupHasher,
+		chunksPerGenerator: chunksPerGenerator,
+		maxInputBits:       maxInputBits,
 	}, nil
 }
 
+// table returns the window table of segment i, deriving its generator
+// and the ones before it if needed
+func (hasher *PedersenHasher) table(i int) (segmentTable, error) {
+	hasher.mu.RLock()
+	if i < len(hasher.tables) {
+		t := hasher.tables[i]
+		hasher.mu.RUnlock()
+		return t, nil
+	}
+	hasher.mu.RUnlock()
+
+	hasher.mu.Lock()
+	defer hasher.mu.Unlock()
+	for j := len(hasher.tables); j <= i; j++ {
+		msg := make([]byte, 4)
+		binary.LittleEndian.PutUint32(msg, uint32(j))
+
+		p, err := hasher.groupHasher.FindGroupHash(msg)
+		if err != nil {
+			return nil, err
+		}
+		hasher.tables = append(hasher.tables, newSegmentTable(extended.FromJubjubPoint(p), hasher.chunksPerGenerator))
+	}
+	return hasher.tables[i], nil
+}
+
 func (hasher *PedersenHasher) PedersenHashForBits(personalization []bool, bitsToHash []bool) (*jubjub.JubjubPoint, error) {
-	bits := append(personalization, bitsToHash...)
-	sum, err := hasher.curve.Point(big.NewInt(0), big.NewInt(1))
+	p, err := hasher.hashToPoint(personalization, bitsToHash)
 	if err != nil {
 		return nil, err
 	}
-	sumS := big.NewInt(0)
-	for i := 0; i < divCeil(len(bits), 3); i++ {
-		// fmt.Printf("i: %d\n", i)
-		g := hasher.generators[i/(hasher.chunksPerGenerator)]
-		chunk := make([]int, 3)
-		for j := 0; j < 3; j++ {
-			if ((3*i + j) < len(bits)) && bits[3*i+j] {
-				chunk[j] = 1
-			}
-		}
+	return p.ToJubjubPoint(hasher.curve)
+}
 
-		s := (1 - 2*chunk[2]) * (1 + chunk[0] + 2*chunk[1])
-		bigS := big.NewInt(int64(s))
-		powerOf2 := big.NewInt(2)
-		powerOf2.Exp(powerOf2, big.NewInt(int64(4*(i%hasher.chunksPerGenerator))), hasher.curve.JubjubS)
-		bigS.Mul(bigS, powerOf2)
-		bigS.Mod(bigS, hasher.curve.JubjubS)
-		sumS.Add(sumS, bigS)
-		// fmt.Printf("chunk = %v, %v\n", chunk, err)
-		// fmt.Printf("bigS = %v, %v\n", bigS, err)
-
-		if i%(hasher.chunksPerGenerator) == (hasher.chunksPerGenerator-1) || i == divCeil(len(bits), 3)-1 {
-			withScalar, err := hasher.curve.ScalarMult(sumS, g)
-			if err != nil {
-				return nil, err
-			}
-			sum, err = hasher.curve.Add(sum, withScalar)
-			if err != nil {
-				return nil, err
-			}
-			sumS = big.NewInt(0)
-			// fmt.Printf("i = %d\n", i)
-			// fmt.Printf("withScalar = %v\n", withScalar)
-			// fmt.Printf("sum = %v\n", sum)
-		}
-		// fmt.Printf("sum = %v, %v\n", sum, err)
+// hashToPoint computes PedersenHashToPoint on the extended backend with
+// one table lookup and one addition per 3-bit chunk
+func (hasher *PedersenHasher) hashToPoint(personalization []bool, bitsToHash []bool) (*extended.ExtendedPoint, error) {
+	s := hasher.NewStream(personalization)
+	if err := s.WriteBits(bitsToHash); err != nil {
+		return nil, err
 	}
-
-	return sum, nil
+	return s.SumToPoint()
 }
@@ -0,0 +1,333 @@
+package pedersenhash
+
+import (
+	"encoding/binary"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fr"
+	"github.com/mechanizm/jubjub/futil"
+	"github.com/mechanizm/jubjub/grouphash"
+)
+
+// referenceHash computes PedersenHashToPoint with the big.Int backend,
+// following the Sapling specification: the padded input is split into
+// segments of the given number of chunks, and segment j is multiplied by
+// the generator GroupHash(domain, LE32(j)).
+func referenceHash(t *testing.T, domain string, chunks int, bits []bool) *jubjub.JubjubPoint {
+	curve := jubjub.NewJubjub()
+	hasher, err := grouphash.NewGroupHasher([]byte(domain))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for len(bits)%3 != 0 {
+		bits = append(bits, false)
+	}
+
+	sum, err := curve.Point(big.NewInt(0), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for j := 0; 3*chunks*j < len(bits); j++ {
+		msg := make([]byte, 4)
+		binary.LittleEndian.PutUint32(msg, uint32(j))
+		g, err := hasher.FindGroupHash(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// <M_j> = sum of enc(m_i).2^(4.(i-1))
+		enc := new(big.Int)
+		for i := 0; i < chunks && 3*(chunks*j+i) < len(bits); i++ {
+			c := bits[3*(chunks*j+i):]
+			s := int64(1)
+			if c[0] {
+				s++
+			}
+			if c[1] {
+				s += 2
+			}
+			if c[2] {
+				s = -s
+			}
+			term := new(big.Int).Lsh(big.NewInt(s), uint(4*i))
+			enc.Add(enc, term)
+		}
+		enc.Mod(enc, curve.JubjubS)
+
+		p, err := curve.ScalarMult(enc, g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum, err = curve.Add(sum, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return sum
+}
+
+func testBits(n int) []bool {
+	bits := make([]bool, n)
+	for i := range bits {
+		bits[i] = (i*7+3)%5 < 2
+	}
+	return bits
+}
+
+func TestSegmentBoundaries(t *testing.T) {
+	hasher, err := NewPedersenHasher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	personalization := NoteCommitment.Bits()
+
+	// A segment holds 63 chunks of 3 bits. Seven segments need a
+	// generator beyond the six fixed ones.
+	for _, segments := range []int{1, 5, 6, 7} {
+		for _, delta := range []int{-1, 0, 1} {
+			n := 189*segments + delta - len(personalization)
+			bits := testBits(n)
+
+			got, err := hasher.PedersenHashForBits(personalization, bits)
+			if err != nil {
+				t.Fatalf("%d bits: %v", n, err)
+			}
+			want := referenceHash(t, "Zcash_PH", 63, append(NoteCommitment.Bits(), bits...))
+			if got.X().Cmp(want.X()) != 0 || got.Y().Cmp(want.Y()) != 0 {
+				t.Errorf("%d bits: got %s, want %s", n, got, want)
+			}
+		}
+	}
+}
+
+func TestMaxInputBits(t *testing.T) {
+	hasher, err := NewPedersenHasherWithMaxInputBits(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hasher.PedersenHashForBits(NoteCommitment.Bits(), testBits(100)); err != nil {
+		t.Errorf("100 bits: %v", err)
+	}
+	if _, err := hasher.PedersenHashForBits(NoteCommitment.Bits(), testBits(101)); err != ErrInputTooLong {
+		t.Errorf("101 bits: expected ErrInputTooLong, got %v", err)
+	}
+
+	if _, err := NewPedersenHasherWithMaxInputBits(-1); err != ErrInvalidLength {
+		t.Errorf("expected ErrInvalidLength, got %v", err)
+	}
+}
+
+func TestConcurrentGeneratorDerivation(t *testing.T) {
+	hasher, err := NewPedersenHasher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bits := testBits(189 * 8)
+	want, err := hasher.PedersenHashForBits(nil, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh hasher derives the extra generators while it is shared
+	fresh, err := NewPedersenHasher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := fresh.PedersenHashForBits(nil, bits)
+			if err != nil || got.String() != want.String() {
+				t.Errorf("concurrent hash gave %v, %v", got, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestParams(t *testing.T) {
+	sapling, err := NewPedersenHasher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	derived, err := NewPedersenHasherWithParams([]byte("Zcash_PH"), 63, nil, DefaultMaxInputBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewPedersenHasherWithParams([]byte("MyApp_PH"), 20, nil, DefaultMaxInputBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bits := testBits(400)
+	want, err := sapling.PedersenHashForBits(nil, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := derived.PedersenHashForBits(nil, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("derived Sapling generators give %s, want %s", got, want)
+	}
+
+	got, err = other.PedersenHashForBits(nil, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other := referenceHash(t, "MyApp_PH", 20, bits); got.String() != other.String() {
+		t.Errorf("got %s, want %s", got, other)
+	}
+	if got.String() == want.String() {
+		t.Error("separate domains give the same hash")
+	}
+}
+
+func TestParamsGenerators(t *testing.T) {
+	g := extended.BasePoint()
+	hasher, err := NewPedersenHasherWithParams([]byte("MyApp_PH"), 1, []*extended.ExtendedPoint{g}, DefaultMaxInputBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// enc(100) = 2 with the given generator, then enc(111) = -4 with
+	// the first derived one
+	got, err := hasher.hashToPoint(nil, []bool{true, false, false, true, true, true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	derived, err := grouphash.NewGroupHasher([]byte("MyApp_PH"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := derived.FindGroupHash([]byte{1, 0, 0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h4 := extended.FromJubjubPoint(h).Double().Double()
+	if want := g.Double().Sub(h4); !got.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestParamsErrors(t *testing.T) {
+	g := extended.BasePoint()
+	torsion := extended.EightTorsion()[1]
+	for _, tc := range []struct {
+		personalization []byte
+		chunks          int
+		gens            []*extended.ExtendedPoint
+		maxInputBits    int
+		err             error
+	}{
+		{[]byte("Zcash_PH"), 0, nil, 0, ErrInvalidChunksPerGenerator},
+		{[]byte("Zcash_PH"), 64, nil, 0, ErrInvalidChunksPerGenerator},
+		{[]byte("Zcash_PH_"), 63, nil, 0, ErrInvalidLength},
+		{[]byte("Zcash_PH"), 63, nil, -1, ErrInvalidLength},
+		{[]byte("Zcash_PH"), 63, []*extended.ExtendedPoint{g, extended.Identity()}, 0, ErrSmallOrder},
+		{[]byte("Zcash_PH"), 63, []*extended.ExtendedPoint{torsion}, 0, ErrNotInSubgroup},
+		{[]byte("Zcash_PH"), 63, []*extended.ExtendedPoint{g.Add(torsion)}, 0, ErrNotInSubgroup},
+	} {
+		if _, err := NewPedersenHasherWithParams(tc.personalization, tc.chunks, tc.gens, tc.maxInputBits); err != tc.err {
+			t.Errorf("%q, %d chunks: got %v, want %v", tc.personalization, tc.chunks, err, tc.err)
+		}
+	}
+}
+
+// scalarHashToPoint is the implementation that predates the window
+// tables: the chunks of a segment are summed into a scalar with big.Int
+// arithmetic, which is then multiplied by the generator
+func scalarHashToPoint(hasher *PedersenHasher, bits []bool) (*extended.ExtendedPoint, error) {
+	sum := extended.Identity()
+	sumS := big.NewInt(0)
+	chunks := divCeil(len(bits), 3)
+	for i := 0; i < chunks; i++ {
+		chunk := make([]int, 3)
+		for j := 0; j < 3; j++ {
+			if ((3*i + j) < len(bits)) && bits[3*i+j] {
+				chunk[j] = 1
+			}
+		}
+
+		s := (1 - 2*chunk[2]) * (1 + chunk[0] + 2*chunk[1])
+		bigS := big.NewInt(int64(s))
+		powerOf2 := big.NewInt(2)
+		powerOf2.Exp(powerOf2, big.NewInt(int64(4*(i%hasher.chunksPerGenerator))), hasher.curve.JubjubS)
+		bigS.Mul(bigS, powerOf2)
+		sumS.Add(sumS, bigS)
+
+		if i%hasher.chunksPerGenerator == hasher.chunksPerGenerator-1 || i == chunks-1 {
+			table, err := hasher.table(i / hasher.chunksPerGenerator)
+			if err != nil {
+				return nil, err
+			}
+			sumS.Mod(sumS, hasher.curve.JubjubS)
+			byt := make([]byte, 32)
+			sumS.FillBytes(byt)
+			futil.Reverse(byt)
+			s, err := fr.FromBytes(byt)
+			if err != nil {
+				return nil, err
+			}
+			sum = sum.Add(table[0][0].Mul(s))
+			sumS = big.NewInt(0)
+		}
+	}
+	return sum, nil
+}
+
+func TestWindowedMatchesScalar(t *testing.T) {
+	hasher, err := NewPedersenHasher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{0, 1, 2, 3, 510, 189 * 7} {
+		bits := testBits(n)
+		got, err := hasher.hashToPoint(merkleTree(t, 0).Bits(), bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := scalarHashToPoint(hasher, append(merkleTree(t, 0).Bits(), bits...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("%d bits: got %s, want %s", n, got, want)
+		}
+	}
+}
+
+// BenchmarkMerkleHash hashes a Merkle tree node, two 255-bit field
+// elements
+func BenchmarkMerkleHash(b *testing.B) {
+	hasher, _ := NewPedersenHasher()
+	bits := testBits(510)
+	personalization := merkleTree(b, 0).Bits()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hasher.hashToPoint(personalization, bits)
+	}
+}
+
+func BenchmarkMerkleHashScalarMul(b *testing.B) {
+	hasher, _ := NewPedersenHasher()
+	bits := append(merkleTree(b, 0).Bits(), testBits(510)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scalarHashToPoint(hasher, bits)
+	}
+}
+
+func BenchmarkNewPedersenHasher(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		NewPedersenHasher()
+	}
+}
@@ -0,0 +1,76 @@
+package pedersenhash
+
+import (
+	"sync"
+
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fq"
+)
+
+// Personalization is the 6-bit domain separator that Sapling prepends to
+// the input of the Pedersen hash, as the integer whose little-endian
+// bits are prepended
+type Personalization uint8
+
+// NoteCommitment is the personalization of note commitments, 1^6
+const NoteCommitment Personalization = 0b11_1111
+
+// MerkleTree returns the personalization of the note commitment tree
+// hash at the given depth, I2LEBSP_6(depth), where depth 0 hashes two
+// leaves. It returns ErrInvalidDepth unless 0 <= depth < 63, since
+// depth 63 would collide with NoteCommitment.
+func MerkleTree(depth int) (Personalization, error) {
+	if depth < 0 || depth >= int(NoteCommitment) {
+		return 0, ErrInvalidDepth
+	}
+	return Personalization(depth), nil
+}
+
+// Bits returns the 6 bits of p in little-endian order
+func (p Personalization) Bits() []bool {
+	bits := make([]bool, 6)
+	for i := range bits {
+		bits[i] = (p>>i)&1 == 1
+	}
+	return bits
+}
+
+var (
+	defaultHasherOnce sync.Once
+	defaultHasher     *PedersenHasher
+)
+
+func saplingHasher() *PedersenHasher {
+	defaultHasherOnce.Do(func() {
+		// NewPedersenHasher only uses the fixed generators and cannot fail
+		defaultHasher, _ = NewPedersenHasher()
+	})
+	return defaultHasher
+}
+
+// PedersenHash returns the Sapling PedersenHash, Extract_J of
+// PedersenHashToPoint, of the first bitLen bits of data. The bits of each
+// byte are taken from the least significant one (LEOS2BSP).
+func PedersenHash(personalization Personalization, data []byte, bitLen int) (*fq.Fq, error) {
+	if bitLen < 0 || bitLen > 8*len(data) {
+		return nil, ErrInvalidLength
+	}
+
+	bits := make([]bool, bitLen)
+	for i := range bits {
+		bits[i] = (data[i/8]>>(i%8))&1 == 1
+	}
+
+	p, err := saplingHasher().hashToPoint(personalization.Bits(), bits)
+	if err != nil {
+		return nil, err
+	}
+	return extended.Extract(p), nil
+}
+
+// NewStream returns a Stream that computes PedersenHash with the given
+// personalization. Its Sum is the same as PedersenHash of the
+// concatenation of the data written to it.
+func NewStream(personalization Personalization) *Stream {
+	return saplingHasher().NewStream(personalization.Bits())
+}
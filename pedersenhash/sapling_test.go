@@ -0,0 +1,135 @@
+package pedersenhash
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/mechanizm/jubjub/fq"
+	"github.com/mechanizm/jubjub/futil"
+)
+
+func merkleTree(tb testing.TB, depth int) Personalization {
+	p, err := MerkleTree(depth)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return p
+}
+
+// merkleCRH packs the 255 low bits of left and right into 510 bits and
+// hashes them at the given depth
+func merkleCRH(t *testing.T, depth int, left, right *fq.Fq) *fq.Fq {
+	data := make([]byte, 64)
+	for i, f := range []*fq.Fq{left, right} {
+		byt := f.Bytes()
+		for j := 0; j < 255; j++ {
+			if (byt[j/8]>>(j%8))&1 == 1 {
+				k := 255*i + j
+				data[k/8] |= 1 << (k % 8)
+			}
+		}
+	}
+
+	h, err := PedersenHash(merkleTree(t, depth), data, 510)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// Roots of the empty Sapling note commitment tree of depth 1 to 32, as in
+// librustzcash and zcashd. The empty leaf is Uncommitted = 1.
+var emptyRoots = map[int]string{
+	1:  "817de36ab2d57feb077634bca77819c8e0bd298c04f6fed0e6a83cc1356ca155",
+	2:  "ffe9fc03f18b176c998806439ff0bb8ad193afdb27b2ccbc88856916dd804e34",
+	3:  "d8283386ef2ef07ebdbb4383c12a739a953a4d6e0d6fb1139a4036d693bfbb6c",
+	4:  "e110de65c907b9dea4ae0bd83a4b0a51bea175646a64c12b4c9f931b2cb31b49",
+	16: "1ea6675f9551eeb9dfaaa9247bc9858270d3d3a4c5afa7177a984d5ed1be2451",
+	31: "b2eed031d4d6a4f02a097f80b54cc1541d4163c6b6f5971f88b6e41d35c53814",
+	32: "fbc2f4300c01f0b7820d00e3347c8da4ee614674376cbc45359daa54f9b5493e",
+}
+
+func TestEmptyRoots(t *testing.T) {
+	root := fq.One()
+	for depth := 0; depth < 32; depth++ {
+		root = merkleCRH(t, depth, root, root)
+		if want, ok := emptyRoots[depth+1]; ok {
+			if got := hex.EncodeToString(root.Bytes()); got != want {
+				t.Errorf("empty root of depth %d = %s, want %s", depth+1, got, want)
+			}
+		}
+	}
+}
+
+func TestPersonalizationBits(t *testing.T) {
+	if got := NoteCommitment.Bits(); !equalBits(got, []bool{true, true, true, true, true, true}) {
+		t.Errorf("NoteCommitment.Bits() = %v", got)
+	}
+	if got := merkleTree(t, 0).Bits(); !equalBits(got, make([]bool, 6)) {
+		t.Errorf("MerkleTree(0).Bits() = %v", got)
+	}
+	// 25 = 0b011001
+	if got := merkleTree(t, 25).Bits(); !equalBits(got, []bool{true, false, false, true, true, false}) {
+		t.Errorf("MerkleTree(25).Bits() = %v", got)
+	}
+	for _, depth := range []int{-1, 63, 64} {
+		if _, err := MerkleTree(depth); err != ErrInvalidDepth {
+			t.Errorf("MerkleTree(%d): expected ErrInvalidDepth, got %v", depth, err)
+		}
+	}
+}
+
+func TestPedersenHashMatchesBits(t *testing.T) {
+	hasher, err := NewPedersenHasher()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte{0x5a, 0xc3, 0xff, 0x01, 0x80}
+	for _, bitLen := range []int{0, 1, 3, 8, 13, 40} {
+		bits := make([]bool, bitLen)
+		for i := range bits {
+			bits[i] = (data[i/8]>>(i%8))&1 == 1
+		}
+		want, err := hasher.PedersenHashForBits(NoteCommitment.Bits(), bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := PedersenHash(NoteCommitment, data, bitLen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fqToBig(got).Cmp(want.X()) != 0 {
+			t.Errorf("bitLen %d: PedersenHash = %s, want %x", bitLen, got, want.X())
+		}
+	}
+}
+
+func TestPedersenHashInvalidLength(t *testing.T) {
+	data := make([]byte, 4)
+	for _, bitLen := range []int{-1, 33} {
+		if _, err := PedersenHash(NoteCommitment, data, bitLen); err != ErrInvalidLength {
+			t.Errorf("bitLen %d: expected ErrInvalidLength, got %v", bitLen, err)
+		}
+	}
+}
+
+func equalBits(a, b []bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func fqToBig(f *fq.Fq) *big.Int {
+	byt := f.Bytes()
+	futil.Reverse(byt)
+	return new(big.Int).SetBytes(byt)
+}
@@ -0,0 +1,126 @@
+package pedersenhash
+
+import (
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fq"
+)
+
+// Stream computes a Pedersen hash incrementally. Each complete 3-bit
+// chunk is added to a running sum as soon as it is written, so only the
+// last incomplete chunk is buffered. A Stream must not be used
+// concurrently.
+type Stream struct {
+	hasher *PedersenHasher
+
+	sum     *extended.ExtendedPoint
+	table   segmentTable
+	chunk   int // index of the next chunk
+	pending []bool
+	written int // number of input bits, not counting the personalization
+	err     error
+}
+
+// NewStream returns a Stream that hashes personalization followed by the
+// bits written to it
+func (hasher *PedersenHasher) NewStream(personalization []bool) *Stream {
+	s := &Stream{
+		hasher:  hasher,
+		sum:     extended.Identity(),
+		pending: make([]bool, 0, 3),
+	}
+	s.writeBits(personalization)
+	return s
+}
+
+// WriteBits appends bits to the input. It returns ErrInputTooLong,
+// without writing any of them, if the input would become longer than the
+// hasher's maximum.
+func (s *Stream) WriteBits(bits []bool) error {
+	if s.err != nil {
+		return s.err
+	}
+	if len(bits) > s.hasher.maxInputBits-s.written {
+		return ErrInputTooLong
+	}
+	s.written += len(bits)
+	s.writeBits(bits)
+	return s.err
+}
+
+// Write appends the bits of p to the input, taking the bits of each byte
+// from the least significant one as PedersenHash does. It implements
+// io.Writer.
+func (s *Stream) Write(p []byte) (int, error) {
+	bits := make([]bool, 8*len(p))
+	for i := range bits {
+		bits[i] = (p[i/8]>>(i%8))&1 == 1
+	}
+	if err := s.WriteBits(bits); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *Stream) writeBits(bits []bool) {
+	for _, bit := range bits {
+		s.pending = append(s.pending, bit)
+		if len(s.pending) == 3 {
+			s.sum, s.err = s.addChunk(s.sum, s.pending)
+			if s.err != nil {
+				return
+			}
+			s.pending = s.pending[:0]
+			s.chunk++
+		}
+	}
+}
+
+// addChunk returns sum plus enc(chunk) times the chunk's multiple of its
+// segment generator, enc(chunk) = (1 - 2.s2).(1 + s0 + 2.s1)
+func (s *Stream) addChunk(sum *extended.ExtendedPoint, chunk []bool) (*extended.ExtendedPoint, error) {
+	j := s.chunk % s.hasher.chunksPerGenerator
+	if j == 0 || s.table == nil {
+		table, err := s.hasher.table(s.chunk / s.hasher.chunksPerGenerator)
+		if err != nil {
+			return nil, err
+		}
+		s.table = table
+	}
+
+	m := 0
+	if chunk[0] {
+		m++
+	}
+	if chunk[1] {
+		m += 2
+	}
+	if chunk[2] {
+		return sum.SubAffineNiels(s.table[j][m]), nil
+	}
+	return sum.AddAffineNiels(s.table[j][m]), nil
+}
+
+// SumToPoint returns PedersenHashToPoint of the input written so far,
+// padding the last chunk with zeros. It does not change the state of the
+// Stream, so more bits can be written afterwards.
+func (s *Stream) SumToPoint() (*extended.ExtendedPoint, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if len(s.pending) == 0 {
+		return s.sum, nil
+	}
+
+	chunk := make([]bool, 3)
+	copy(chunk, s.pending)
+	return s.addChunk(s.sum, chunk)
+}
+
+// Sum returns Extract_J of SumToPoint, the u-coordinate of the hash
+func (s *Stream) Sum() (*fq.Fq, error) {
+	p, err := s.SumToPoint()
+	if err != nil {
+		return nil, err
+	}
+	return extended.Extract(p), nil
+}
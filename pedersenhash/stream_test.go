@@ -0,0 +1,96 @@
+package pedersenhash
+
+import "testing"
+
+func TestStreamSplits(t *testing.T) {
+	hasher, err := NewPedersenHasher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	personalization := merkleTree(t, 3).Bits()
+	bits := testBits(2*189 + 4)
+	want, err := hasher.hashToPoint(personalization, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for split := 0; split <= len(bits); split += 7 {
+		s := hasher.NewStream(personalization)
+		if err := s.WriteBits(bits[:split]); err != nil {
+			t.Fatal(err)
+		}
+		// an intermediate sum must not change the result
+		if _, err := s.SumToPoint(); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.WriteBits(bits[split:]); err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.SumToPoint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("split at %d: got %s, want %s", split, got, want)
+		}
+	}
+}
+
+func TestStreamWrite(t *testing.T) {
+	data := []byte("a Pedersen hash of a few bytes, written in pieces")
+	want, err := PedersenHash(NoteCommitment, data, 8*len(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewStream(NoteCommitment)
+	for i := 0; i < len(data); i += 5 {
+		end := i + 5
+		if end > len(data) {
+			end = len(data)
+		}
+		if n, err := s.Write(data[i:end]); err != nil || n != end-i {
+			t.Fatalf("Write returned %d, %v", n, err)
+		}
+	}
+	got, err := s.Sum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestStreamMaxInputBits(t *testing.T) {
+	hasher, err := NewPedersenHasherWithMaxInputBits(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := hasher.NewStream(NoteCommitment.Bits())
+	if _, err := s.Write([]byte{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Write([]byte{3}); err != ErrInputTooLong {
+		t.Errorf("expected ErrInputTooLong, got %v", err)
+	}
+	if err := s.WriteBits(testBits(4)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WriteBits(testBits(1)); err != ErrInputTooLong {
+		t.Errorf("expected ErrInputTooLong, got %v", err)
+	}
+
+	got, err := s.SumToPoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bits := append([]bool{true, false, false, false, false, false, false, false, false, true}, make([]bool, 6)...)
+	want, err := hasher.hashToPoint(NoteCommitment.Bits(), append(bits, testBits(4)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(want) {
+		t.Errorf("rejected writes changed the hash")
+	}
+}
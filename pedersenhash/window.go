@@ -0,0 +1,30 @@
+package pedersenhash
+
+import "github.com/mechanizm/jubjub/extended"
+
+// segmentTable holds, for each chunk j of a segment with generator G,
+// the multiples m.2^(4j).G for m = 1..4 at index m-1. These are the
+// values the Sapling circuit selects with the first two bits of a chunk,
+// before conditionally negating with the third.
+type segmentTable [][4]*extended.AffineNielsPoint
+
+func newSegmentTable(g *extended.ExtendedPoint, chunks int) segmentTable {
+	points := make([]*extended.ExtendedPoint, 0, 4*chunks)
+	base := g
+	for j := 0; j < chunks; j++ {
+		double := base.Double()
+		quadruple := double.Double()
+		points = append(points, base, double, double.Add(base), quadruple)
+
+		// 2^(4(j+1)).G
+		base = quadruple.Double().Double()
+	}
+
+	// one inversion for the whole table
+	niels := extended.BatchToAffineNiels(points)
+	table := make(segmentTable, chunks)
+	for j := range table {
+		copy(table[j][:], niels[4*j:])
+	}
+	return table
+}
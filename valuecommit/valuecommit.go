@@ -0,0 +1,146 @@
+// Package valuecommit implements the homomorphic value commitments of
+// Sapling and the binding keys that prove a transaction balances.
+//
+// A value commitment to v with trapdoor rcv is [v]V + [rcv]R, where V and
+// R are ValueCommitmentValueBase and ValueCommitmentRandomnessBase. Sums
+// of commitments commit to the sums of the values and of the trapdoors.
+package valuecommit
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"sync"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fr"
+	"github.com/mechanizm/jubjub/generators"
+)
+
+var (
+	ErrInvalidLength = jubjub.ErrInvalidLength
+	ErrSmallOrder    = jubjub.ErrSmallOrder
+)
+
+var (
+	basesOnce             sync.Once
+	valueBase, randomBase *extended.AffineNielsPoint
+)
+
+func bases() (*extended.AffineNielsPoint, *extended.AffineNielsPoint) {
+	basesOnce.Do(func() {
+		valueBase = generators.ValueCommitmentValueBase().ToAffineNiels()
+		randomBase = generators.ValueCommitmentRandomnessBase().ToAffineNiels()
+	})
+	return valueBase, randomBase
+}
+
+// ValueCommitment is a commitment to a signed value
+type ValueCommitment struct {
+	p *extended.ExtendedPoint
+}
+
+// Commit returns the commitment to value with trapdoor rcv
+func Commit(value int64, rcv *fr.Fr) *ValueCommitment {
+	v, r := bases()
+	return &ValueCommitment{p: v.Mul(valueScalar(value)).Add(r.Mul(rcv))}
+}
+
+// RandomTrapdoor returns a uniformly random trapdoor, reducing 64 bytes
+// read from rand
+func RandomTrapdoor(rand io.Reader) (*fr.Fr, error) {
+	byt := make([]byte, 64)
+	if _, err := io.ReadFull(rand, byt); err != nil {
+		return nil, err
+	}
+	return fr.FromBytesWide(byt)
+}
+
+// FromBytes decodes a value commitment, rejecting encodings that are not
+// canonical and points of small order as Sapling consensus does
+func FromBytes(byt []byte) (*ValueCommitment, error) {
+	p, err := extended.FromBytes(byt)
+	if err != nil {
+		return nil, err
+	}
+	if p.IsSmallOrder() {
+		return nil, ErrSmallOrder
+	}
+	return &ValueCommitment{p: p}, nil
+}
+
+// Point returns the commitment as a curve point
+func (cv *ValueCommitment) Point() *extended.ExtendedPoint {
+	return cv.p
+}
+
+func (cv *ValueCommitment) Bytes() []byte {
+	return cv.p.Bytes()
+}
+
+func (cv *ValueCommitment) Equal(o *ValueCommitment) bool {
+	return cv.p.Equal(o.p)
+}
+
+// Add returns the commitment to the sum of the values, with the sum of
+// the trapdoors
+func (cv *ValueCommitment) Add(o *ValueCommitment) *ValueCommitment {
+	return &ValueCommitment{p: cv.p.Add(o.p)}
+}
+
+// Sub returns the commitment to the difference of the values, with the
+// difference of the trapdoors
+func (cv *ValueCommitment) Sub(o *ValueCommitment) *ValueCommitment {
+	return &ValueCommitment{p: cv.p.Sub(o.p)}
+}
+
+func (cv *ValueCommitment) String() string {
+	return hex.EncodeToString(cv.Bytes())
+}
+
+// BindingVerificationKey returns bvk, the sum of the spend commitments
+// minus the sum of the output commitments minus the commitment to
+// valueBalance with a zero trapdoor. If the values balance, bvk is
+// [bsk]R for the bsk returned by BindingSigningKey.
+func BindingVerificationKey(spends, outputs []*ValueCommitment, valueBalance int64) *extended.ExtendedPoint {
+	bvk := extended.Identity()
+	for _, cv := range spends {
+		bvk = bvk.Add(cv.p)
+	}
+	for _, cv := range outputs {
+		bvk = bvk.Sub(cv.p)
+	}
+	return bvk.Sub(Commit(valueBalance, fr.Zero()).p)
+}
+
+// BindingSigningKey returns bsk, the sum of the spend trapdoors minus the
+// sum of the output trapdoors
+func BindingSigningKey(spends, outputs []*fr.Fr) *fr.Fr {
+	bsk := fr.Zero()
+	for _, rcv := range spends {
+		bsk = bsk.Add(rcv)
+	}
+	for _, rcv := range outputs {
+		bsk = bsk.Sub(rcv)
+	}
+	return bsk
+}
+
+// valueScalar maps value to a scalar, negative values to r - |value|
+func valueScalar(value int64) *fr.Fr {
+	// uint64(-value) is also the magnitude of math.MinInt64
+	magnitude := uint64(value)
+	if value < 0 {
+		magnitude = uint64(-value)
+	}
+
+	byt := make([]byte, 32)
+	binary.LittleEndian.PutUint64(byt, magnitude)
+	// byt has the right length and is smaller than r
+	s, _ := fr.FromBytes(byt)
+	if value < 0 {
+		return s.Neg()
+	}
+	return s
+}
@@ -0,0 +1,129 @@
+package valuecommit
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fr"
+	"github.com/mechanizm/jubjub/futil"
+	"github.com/mechanizm/jubjub/generators"
+	"github.com/mechanizm/jubjub/homomorphicpedersencommit"
+)
+
+func trapdoor(t *testing.T) *fr.Fr {
+	rcv, err := RandomTrapdoor(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rcv
+}
+
+func TestCommitMatchesHomomorphicCommitter(t *testing.T) {
+	committer, err := homomorphicpedersencommit.NewCommitter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcv := trapdoor(t)
+	value := int64(3160994844294270608)
+
+	be := rcv.Bytes()
+	futil.Reverse(be)
+	want, err := committer.Commit(big.NewInt(value), new(big.Int).SetBytes(be))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Commit(value, rcv); !got.Point().Equal(extended.FromJubjubPoint(want)) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestCommitNegative(t *testing.T) {
+	rcv := trapdoor(t)
+	for _, value := range []int64{-1, -5000, math.MinInt64 + 1} {
+		got := Commit(value, rcv)
+		want := Commit(0, rcv).Sub(Commit(-value, fr.Zero()))
+		if !got.Equal(want) {
+			t.Errorf("%d: got %s, want %s", value, got, want)
+		}
+	}
+
+	got := Commit(math.MinInt64, fr.Zero())
+	want := Commit(0, fr.Zero()).Sub(Commit(math.MaxInt64, fr.Zero())).Sub(Commit(1, fr.Zero()))
+	if !got.Equal(want) {
+		t.Errorf("MinInt64: got %s, want %s", got, want)
+	}
+}
+
+func TestHomomorphism(t *testing.T) {
+	r1, r2 := trapdoor(t), trapdoor(t)
+	a, b := int64(1_000_000), int64(-2_500_000)
+
+	if sum := Commit(a, r1).Add(Commit(b, r2)); !sum.Equal(Commit(a+b, r1.Add(r2))) {
+		t.Error("sum of commitments does not commit to the sum")
+	}
+	if diff := Commit(a, r1).Sub(Commit(b, r2)); !diff.Equal(Commit(a-b, r1.Sub(r2))) {
+		t.Error("difference of commitments does not commit to the difference")
+	}
+}
+
+func TestBindingKeys(t *testing.T) {
+	spendValues := []int64{10, 5}
+	outputValues := []int64{12}
+
+	var spends, outputs []*ValueCommitment
+	var spendTrapdoors, outputTrapdoors []*fr.Fr
+	for _, v := range spendValues {
+		rcv := trapdoor(t)
+		spends = append(spends, Commit(v, rcv))
+		spendTrapdoors = append(spendTrapdoors, rcv)
+	}
+	for _, v := range outputValues {
+		rcv := trapdoor(t)
+		outputs = append(outputs, Commit(v, rcv))
+		outputTrapdoors = append(outputTrapdoors, rcv)
+	}
+
+	bsk := BindingSigningKey(spendTrapdoors, outputTrapdoors)
+	want := generators.ValueCommitmentRandomnessBase().Mul(bsk)
+
+	if bvk := BindingVerificationKey(spends, outputs, 3); !bvk.Equal(want) {
+		t.Errorf("balanced transaction: bvk %s, want %s", bvk, want)
+	}
+	if bvk := BindingVerificationKey(spends, outputs, 2); bvk.Equal(want) {
+		t.Error("unbalanced transaction has a matching binding key")
+	}
+
+	// no spends or outputs
+	if bvk := BindingVerificationKey(nil, nil, 0); !bvk.IsIdentity() {
+		t.Errorf("empty bvk is %s", bvk)
+	}
+}
+
+func TestFromBytes(t *testing.T) {
+	cv := Commit(42, trapdoor(t))
+	decoded, err := FromBytes(cv.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(cv) || !bytes.Equal(decoded.Bytes(), cv.Bytes()) {
+		t.Error("commitment does not round trip")
+	}
+
+	if _, err := FromBytes(extended.Identity().Bytes()); err != ErrSmallOrder {
+		t.Errorf("expected ErrSmallOrder, got %v", err)
+	}
+	if _, err := FromBytes(cv.Bytes()[:31]); err != ErrInvalidLength {
+		t.Errorf("expected ErrInvalidLength, got %v", err)
+	}
+}
+
+func TestRandomTrapdoorShortRead(t *testing.T) {
+	if _, err := RandomTrapdoor(bytes.NewReader(make([]byte, 63))); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+// Package vectorcommit implements Pedersen commitments to vectors of
+// scalars.
+//
+// A commitment to (v_0, ..., v_{k-1}) with blinding factor b is
+// [b]H + sum([v_i]G_i). The generators are derived from a domain with
+// grouphash, so that nobody knows discrete logarithms between them, and
+// commitments are additively homomorphic in both the values and the
+// blinding factor.
+package vectorcommit
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fr"
+	"github.com/mechanizm/jubjub/grouphash"
+)
+
+var (
+	ErrInvalidLength = jubjub.ErrInvalidLength
+	ErrSmallOrder    = jubjub.ErrSmallOrder
+
+	ErrTooManyValues = errors.New("vectorcommit: more values than generators")
+)
+
+// blindingMsg is the group hash input of H. The generators G_i hash the 4
+// byte LE32(i), so H cannot be one of them.
+var blindingMsg = []byte("H")
+
+// Generators is a set of n value generators G_0, ..., G_{n-1} and the
+// blinding generator H
+type Generators struct {
+	g []*extended.ExtendedPoint
+	h *extended.ExtendedPoint
+}
+
+// NewGenerators derives n value generators as GroupHash(domain, LE32(i))
+// and the blinding generator as GroupHash(domain, "H"). domain is a
+// BLAKE2s personalization of at most 8 bytes.
+func NewGenerators(domain []byte, n int) (*Generators, error) {
+	if len(domain) > 8 || n < 0 {
+		return nil, ErrInvalidLength
+	}
+
+	personalization := make([]byte, 8)
+	copy(personalization, domain)
+	hasher, err := grouphash.NewGroupHasher(personalization)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := hasher.FindGroupHash(blindingMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	g := make([]*extended.ExtendedPoint, n)
+	for i := range g {
+		msg := make([]byte, 4)
+		binary.LittleEndian.PutUint32(msg, uint32(i))
+		p, err := hasher.FindGroupHash(msg)
+		if err != nil {
+			return nil, err
+		}
+		g[i] = extended.FromJubjubPoint(p)
+	}
+
+	return &Generators{g: g, h: extended.FromJubjubPoint(h)}, nil
+}
+
+// Len returns the number of value generators
+func (gens *Generators) Len() int {
+	return len(gens.g)
+}
+
+// Commit returns the commitment to values with blinding factor blind.
+// values may be shorter than the set of generators, the missing values
+// are zero. It returns ErrTooManyValues if it is longer.
+func (gens *Generators) Commit(values []*fr.Fr, blind *fr.Fr) (*Commitment, error) {
+	if len(values) > len(gens.g) {
+		return nil, ErrTooManyValues
+	}
+
+	scalars := make([]*fr.Fr, 0, len(values)+1)
+	scalars = append(scalars, blind)
+	scalars = append(scalars, values...)
+
+	points := make([]*extended.ExtendedPoint, 0, len(values)+1)
+	points = append(points, gens.h)
+	points = append(points, gens.g[:len(values)]...)
+
+	// scalars and points have the same length, so this cannot fail
+	p, _ := extended.MultiScalarMul(scalars, points)
+	return &Commitment{p: p}, nil
+}
+
+// Bytes encodes the generators as LE32(n) || H || G_0 || ... || G_{n-1},
+// with 32 bytes per point
+func (gens *Generators) Bytes() []byte {
+	byt := make([]byte, 4, 4+32*(len(gens.g)+1))
+	binary.LittleEndian.PutUint32(byt, uint32(len(gens.g)))
+	byt = append(byt, gens.h.Bytes()...)
+	for _, g := range gens.g {
+		byt = append(byt, g.Bytes()...)
+	}
+	return byt
+}
+
+// GeneratorsFromBytes decodes the output of Bytes. Every generator must
+// be a canonical encoding of a point of prime order.
+func GeneratorsFromBytes(byt []byte) (*Generators, error) {
+	if len(byt) < 4+32 {
+		return nil, ErrInvalidLength
+	}
+	n := binary.LittleEndian.Uint32(byt)
+	if uint64(len(byt)) != 4+32*(uint64(n)+1) {
+		return nil, ErrInvalidLength
+	}
+
+	points := make([]*extended.ExtendedPoint, n+1)
+	for i := range points {
+		s, err := extended.SubgroupPointFromBytes(byt[4+32*i : 4+32*(i+1)])
+		if err != nil {
+			return nil, err
+		}
+		if s.IsIdentity() {
+			return nil, ErrSmallOrder
+		}
+		points[i] = s.Extended()
+	}
+	return &Generators{g: points[1:], h: points[0]}, nil
+}
+
+// Commitment is a commitment to a vector of scalars
+type Commitment struct {
+	p *extended.ExtendedPoint
+}
+
+// CommitmentFromBytes decodes a commitment
+func CommitmentFromBytes(byt []byte) (*Commitment, error) {
+	p, err := extended.FromBytes(byt)
+	if err != nil {
+		return nil, err
+	}
+	return &Commitment{p: p}, nil
+}
+
+func (c *Commitment) Bytes() []byte {
+	return c.p.Bytes()
+}
+
+func (c *Commitment) Equal(o *Commitment) bool {
+	return c.p.Equal(o.p)
+}
+
+// Add returns the commitment to the sum of the vectors, with the sum of
+// the blinding factors
+func (c *Commitment) Add(o *Commitment) *Commitment {
+	return &Commitment{p: c.p.Add(o.p)}
+}
+
+// Sub returns the commitment to the difference of the vectors, with the
+// difference of the blinding factors
+func (c *Commitment) Sub(o *Commitment) *Commitment {
+	return &Commitment{p: c.p.Sub(o.p)}
+}
+
+// Mul returns the commitment to the vector multiplied by s, with the
+// blinding factor multiplied by s
+func (c *Commitment) Mul(s *fr.Fr) *Commitment {
+	return &Commitment{p: c.p.Mul(s)}
+}
+
+func (c *Commitment) String() string {
+	return hex.EncodeToString(c.Bytes())
+}
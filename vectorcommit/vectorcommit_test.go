@@ -0,0 +1,197 @@
+package vectorcommit
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"fmt"
+	"testing"
+
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fr"
+)
+
+func scalar(t *testing.T, label string, i int) *fr.Fr {
+	digest := sha512.Sum512([]byte(fmt.Sprintf("%s %d", label, i)))
+	s, err := fr.FromBytesWide(digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func vector(t *testing.T, label string, n int) []*fr.Fr {
+	values := make([]*fr.Fr, n)
+	for i := range values {
+		values[i] = scalar(t, label, i)
+	}
+	return values
+}
+
+func commit(t *testing.T, gens *Generators, values []*fr.Fr, blind *fr.Fr) *Commitment {
+	c, err := gens.Commit(values, blind)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCommitDefinition(t *testing.T) {
+	gens, err := NewGenerators([]byte("test_vc"), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, blind := vector(t, "value", 5), scalar(t, "blind", 0)
+
+	want := gens.h.Mul(blind)
+	for i, v := range values {
+		want = want.Add(gens.g[i].Mul(v))
+	}
+	if got := commit(t, gens, values, blind); !got.p.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// missing values are zero
+	padded := append(vector(t, "value", 3), fr.Zero(), fr.Zero())
+	if !commit(t, gens, values[:3], blind).Equal(commit(t, gens, padded, blind)) {
+		t.Error("short vector is not padded with zeros")
+	}
+
+	if _, err := gens.Commit(vector(t, "value", 6), blind); err != ErrTooManyValues {
+		t.Errorf("expected ErrTooManyValues, got %v", err)
+	}
+}
+
+func TestHomomorphism(t *testing.T) {
+	gens, err := NewGenerators([]byte("test_vc"), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := vector(t, "a", 4), vector(t, "b", 4)
+	ra, rb := scalar(t, "ra", 0), scalar(t, "rb", 0)
+	s := scalar(t, "s", 0)
+
+	sum := make([]*fr.Fr, 4)
+	diff := make([]*fr.Fr, 4)
+	scaled := make([]*fr.Fr, 4)
+	for i := range a {
+		sum[i] = a[i].Add(b[i])
+		diff[i] = a[i].Sub(b[i])
+		scaled[i] = a[i].Mul(s)
+	}
+
+	ca, cb := commit(t, gens, a, ra), commit(t, gens, b, rb)
+	if !ca.Add(cb).Equal(commit(t, gens, sum, ra.Add(rb))) {
+		t.Error("Add is not homomorphic")
+	}
+	if !ca.Sub(cb).Equal(commit(t, gens, diff, ra.Sub(rb))) {
+		t.Error("Sub is not homomorphic")
+	}
+	if !ca.Mul(s).Equal(commit(t, gens, scaled, ra.Mul(s))) {
+		t.Error("Mul is not homomorphic")
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	gens, err := NewGenerators([]byte("test_vc"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	longer, err := NewGenerators([]byte("test_vc"), 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewGenerators([]byte("other_vc"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gens.Len() != 3 || longer.Len() != 6 {
+		t.Errorf("got lengths %d and %d", gens.Len(), longer.Len())
+	}
+	points := append([]*extended.ExtendedPoint{gens.h}, gens.g...)
+	for i, p := range points {
+		if !p.IsPrimeOrder() {
+			t.Errorf("generator %d is not of prime order", i)
+		}
+		for j := 0; j < i; j++ {
+			if p.Equal(points[j]) {
+				t.Errorf("generators %d and %d are equal", j, i)
+			}
+		}
+	}
+
+	// a larger set extends a smaller one from the same domain
+	values, blind := vector(t, "value", 3), scalar(t, "blind", 0)
+	if !commit(t, gens, values, blind).Equal(commit(t, longer, values, blind)) {
+		t.Error("generator sets from the same domain disagree")
+	}
+	if commit(t, gens, values, blind).Equal(commit(t, other, values, blind)) {
+		t.Error("separate domains give the same commitment")
+	}
+
+	if _, err := NewGenerators([]byte("too_long_"), 3); err != ErrInvalidLength {
+		t.Errorf("expected ErrInvalidLength, got %v", err)
+	}
+}
+
+func TestGeneratorsSerialization(t *testing.T) {
+	gens, err := NewGenerators([]byte("test_vc"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byt := gens.Bytes()
+	if len(byt) != 4+32*4 {
+		t.Fatalf("encoding has %d bytes", len(byt))
+	}
+
+	decoded, err := GeneratorsFromBytes(byt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded.Bytes(), byt) {
+		t.Error("generators do not round trip")
+	}
+	values, blind := vector(t, "value", 3), scalar(t, "blind", 0)
+	if !commit(t, gens, values, blind).Equal(commit(t, decoded, values, blind)) {
+		t.Error("decoded generators give a different commitment")
+	}
+
+	// one value generator, which is the identity
+	identity := append(append([]byte{1, 0, 0, 0}, byt[4:36]...), extended.Identity().Bytes()...)
+
+	for _, tc := range []struct {
+		name string
+		byt  []byte
+		err  error
+	}{
+		{"truncated", byt[:len(byt)-1], ErrInvalidLength},
+		{"no blinding generator", []byte{0, 0, 0, 0}, ErrInvalidLength},
+		{"wrong count", append([]byte{4, 0, 0, 0}, byt[4:]...), ErrInvalidLength},
+		{"identity", identity, ErrSmallOrder},
+	} {
+		if _, err := GeneratorsFromBytes(tc.byt); err != tc.err {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.err)
+		}
+	}
+
+	torsion := append(append([]byte{}, byt...), gens.g[0].Add(extended.EightTorsion()[1]).Bytes()...)
+	torsion[0] = 4
+	if _, err := GeneratorsFromBytes(torsion); err != extended.ErrNotInSubgroup {
+		t.Errorf("expected ErrNotInSubgroup, got %v", err)
+	}
+}
+
+func TestCommitmentBytes(t *testing.T) {
+	gens, err := NewGenerators([]byte("test_vc"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := commit(t, gens, vector(t, "value", 2), scalar(t, "blind", 0))
+	decoded, err := CommitmentFromBytes(c.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(c) {
+		t.Error("commitment does not round trip")
+	}
+}
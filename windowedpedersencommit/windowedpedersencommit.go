@@ -1,63 +1,65 @@
+// Package windowedpedersencommit implements the windowed Pedersen
+// commitments of Sapling and the note commitments built on them.
 package windowedpedersencommit
 
 import (
-	"fmt"
-	"github.com/mechanizm/jubjub/grouphash"
-	"github.com/mechanizm/jubjub/jubjub"
+	"encoding/binary"
+	"sync"
+
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fq"
+	"github.com/mechanizm/jubjub/fr"
+	"github.com/mechanizm/jubjub/generators"
 	"github.com/mechanizm/jubjub/pedersenhash"
-	"math/big"
 )
 
+var ErrInvalidLength = pedersenhash.ErrInvalidLength
+
 var (
-	domain = []byte("Zcash_PH")
+	randomnessBaseOnce sync.Once
+	randomnessBase     *extended.AffineNielsPoint
 )
 
-type WindowedPedersenCommitter struct {
-	curve *jubjub.Jubjub
-	groupHasher *grouphash.GroupHasher
-	pedersenHasher *pedersenhash.PedersenHasher
-}
-
-func NewCommitter() (*WindowedPedersenCommitter, error) {
-	j := jubjub.NewJubjub()
-	groupHasher, err := grouphash.NewGroupHasher([]byte("Zcash_PH"))
-	if err != nil {
-		return nil, err
-	}
-
-	pedersenHasher, err := pedersenhash.NewPedersenHasher()
-	if err != nil {
-		return nil, err
+// WindowedPedersenCommit returns PedersenHashToPoint(personalization, s)
+// + [r] NoteCommitmentRandomnessBase, where s is the first bitLen bits of
+// data, taken from the least significant bit of each byte
+func WindowedPedersenCommit(personalization pedersenhash.Personalization, data []byte, bitLen int, r *fr.Fr) (*extended.ExtendedPoint, error) {
+	if bitLen < 0 || bitLen > 8*len(data) {
+		return nil, ErrInvalidLength
 	}
 
-	return &WindowedPedersenCommitter{
-		curve:  j,
-		groupHasher: groupHasher,
-		pedersenHasher:pedersenHasher,
-	}, nil
-}
-
-func (committer *WindowedPedersenCommitter) Commit(personalization, s []bool, r *big.Int) (*jubjub.JubjubPoint, error) {
-	p, err := committer.pedersenHasher.PedersenHashForBits(personalization, s)
-	if err != nil {
-		return nil, err
+	bits := make([]bool, bitLen)
+	for i := range bits {
+		bits[i] = (data[i/8]>>(i%8))&1 == 1
 	}
-	fmt.Printf("cm: %v\n", p)
 
-	rBase, err := committer.groupHasher.FindGroupHash([]byte("r"))
-	if err != nil {
+	s := pedersenhash.NewStream(personalization)
+	if err := s.WriteBits(bits); err != nil {
 		return nil, err
 	}
-	rSide, err := committer.curve.ScalarMult(r, rBase)
+	p, err := s.SumToPoint()
 	if err != nil {
 		return nil, err
 	}
-	fmt.Printf("rcm: %v\n", rSide)
 
-	ret, err := committer.curve.Add(p, rSide)
-	if err != nil {
-		return nil, err
-	}
+	randomnessBaseOnce.Do(func() {
+		randomnessBase = generators.NoteCommitmentRandomnessBase().ToAffineNiels()
+	})
+	return p.Add(randomnessBase.Mul(r)), nil
+}
+
+// NoteCommit returns cm_u, the u-coordinate of the Sapling note
+// commitment NoteCommit_rcm(repr(g_d), repr(pk_d), v) of a note of the
+// given value sent to the diversified base g_d and transmission key pk_d
+func NoteCommit(gd, pkd *extended.SubgroupPoint, value uint64, rcm *fr.Fr) *fq.Fq {
+	// I2LEBSP_64(v) || repr(g_d) || repr(pk_d)
+	data := make([]byte, 8, 8+32+32)
+	binary.LittleEndian.PutUint64(data, value)
+	data = append(data, gd.Bytes()...)
+	data = append(data, pkd.Bytes()...)
 
-	return ret, nil
+	// the 582 bits are far below the maximum input length, so the
+	// commitment cannot fail
+	cm, _ := WindowedPedersenCommit(pedersenhash.NoteCommitment, data, 8*len(data), rcm)
+	return extended.Extract(cm)
 }
@@ -0,0 +1,209 @@
+package windowedpedersencommit
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/mechanizm/jubjub"
+	"github.com/mechanizm/jubjub/extended"
+	"github.com/mechanizm/jubjub/fr"
+	"github.com/mechanizm/jubjub/futil"
+	"github.com/mechanizm/jubjub/grouphash"
+	"github.com/mechanizm/jubjub/pedersenhash"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	byt, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return byt
+}
+
+func leToBig(le []byte) *big.Int {
+	be := append([]byte{}, le...)
+	futil.Reverse(be)
+	return new(big.Int).SetBytes(be)
+}
+
+// Note commitments for the inputs of the Sapling key components test
+// vectors of zcash-test-vectors: vector i has spending key [i]^32,
+// note_v = 2548793025584392057432895043257984320.i mod 2^64 and
+// note_r = 8890123457840276890326754358439057438290574382905^(i+1) mod r.
+// g_d is DiversifyHash(d).
+//
+// Only vector 0 is copied from the published vectors. Vectors 1 to 9 are
+// regression vectors generated locally by a separate implementation of
+// the protocol specification, which reproduces vector 0. They have a
+// non-zero value, so they check the I2LEBSP_64(v) prefix, but they have
+// not been compared with the published files.
+var noteCommitVectors = []struct {
+	d, pkd string
+	v      uint64
+	rcm    string
+	cmu    string
+}{
+	{
+		d:   "f19d9b797e39f337445839",
+		pkd: "db4cd2b0aac4f7eb8ca131f16567c445a9555126d3c29f14e3d776e841ae7415",
+		v:   0,
+		rcm: "39176dac39ace4980ecc8d778e89860255ec3615060000000000000000000000",
+		cmu: "cb3cf9153270d57eb914c6c2bcc01850c9fed44fce0806278f083ef2dd076439",
+	},
+	{
+		d:   "aef180f6e34e354b888f81",
+		pkd: "a6b13ea336ddb7a67bb09a0e68e9d3cfb39210831ea3a296ba09a922060fd38b",
+		v:   12227227834928555328,
+		rcm: "478ba0ee6e1a75b600036f26f18b7015ab556beddf8b960238869f89dd804e06",
+		cmu: "b57893500bfb85df2e8b01ac452f89e10e266bcfa31c31b29a53ae72cad46950",
+	},
+	{
+		d:   "7599f0bf9b57cd2dc299b6",
+		pkd: "66141739514b28f05def8a18eeee5eed4d44c6225c3c65d88dd9907708012f5a",
+		v:   6007711596147559040,
+		rcm: "147cf2b51b4c7c63cb77b99e8b783e5b5111db0a7ca04d6c014a1d7da83bae0a",
+		cmu: "db85a70a98437f73167fc332d5b7b7408296661770b101b0aa87839f4e55f151",
+	},
+	{
+		d:   "1b81614f1dadea0f8d0a58",
+		pkd: "25eb55fccf761fc64e85a588efe6ead7832fb1f0f7a83165895bdff942925f5c",
+		v:   18234939431076114368,
+		rcm: "34a4b2a9144ff5ea54efee87cf901b5bed5e35d21fbbd788d5bd9d833e112804",
+		cmu: "e08ce482b3a8fb3b35ccdbe34337bd105d8839212e0d1644b9d55caa60d19b6c",
+	},
+	{
+		d:   "fcfb68a40d4bc6a04b09c4",
+		pkd: "8b2a337f03622c24ff381d4c546f6977f90522e92fde44c9d1bb099714b9db2b",
+		v:   12015423192295118080,
+		rcm: "e557851355747c09ac59013cbde85980964ec1844d9c6967ca0c029c8457bb04",
+		cmu: "bdc854bf3e7b00821f3b8b85238ccf1e6715bfe70b632d044b26fb2bc71b7f36",
+	},
+	{
+		d:   "eb519882ad1e5cc654cd59",
+		pkd: "6b27daccb5a8207f532d10ca238f9786648a11b5966e51a2f7d89e15d29b8fdf",
+		v:   5795906953514121792,
+		rcm: "68f06104606b0c5449845ff4c65f73e90f45ef5a43c9d74cb2c85cf56c94c002",
+		cmu: "e8267d30ac11c100bc7a0fdf91f71d74c5bcf2e1ef95669044730169de1a5b4c",
+	},
+	{
+		d:   "bebb0fb46b8aaff89040f6",
+		pkd: "d11da01f0b43bdd5288d32385b8771d223493c69802544043f77cf1d71c1cb8c",
+		v:   18023134788442677120,
+		rcm: "49f90b47fd52fee7c1c81f0dcb5b74c3fb9b3e03976f8b7524eabad008892107",
+		cmu: "572ba20525b0ac4d6dc01ac2ea1090b6e0f2f4bf4ec4a0db5bbccb5b783a1e55",
+	},
+	{
+		d:   "ad6e2e185a3100e3a6a8b3",
+		pkd: "32cb2806b882f1368b0d4a898f72c4c8f728132cc12456946e7f4cb0fb058da9",
+		v:   11803618549661680832,
+		rcm: "5165aff22dd4ed56b4d81d1f171cc3d6432fed1bebf20a7beab12db142f94a0c",
+		cmu: "ab7fc566873ccde671f59827678560a006f82bb7adcd75223fa85936f78c2b23",
+	},
+	{
+		d:   "21c90e1c658b3efe86af58",
+		pkd: "9e64174b4ab981405c323b5e12475945a46d4fedf8060828041cd20e62fd2cef",
+		v:   5584102310880684544,
+		rcm: "8c3e56449dc86354d33b025ef2793460bcb169f3324e4a6b64baa60832315704",
+		cmu: "7b48a8375d3ebd56bc649bb5b5242336c2a05a0803239b5b88fd92078fea4d04",
+	},
+	{
+		d:   "233c4ab886a55e3ba374c0",
+		pkd: "b68e9ee0c0678d7b3036931c831a25255f7ee487385a30316e15f6482b874fda",
+		v:   17811330145809239872,
+		rcm: "6ebbed743619a256f9ad2e85880cfaa9098a5fdb1629990d9a7d3bb93fc90003",
+		cmu: "d376a7bee8ce67f4efde56aa77cf64419b0e550abbcb8e2bcbda8b63e41deb37",
+	},
+}
+
+func TestNoteCommitVectors(t *testing.T) {
+	diversifier, err := grouphash.NewGroupHasher([]byte("Zcash_gd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, v := range noteCommitVectors {
+		p, err := diversifier.Hash(decodeHex(t, v.d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gd, err := extended.SubgroupPointFromExtended(extended.FromJubjubPoint(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk, err := extended.SubgroupPointFromBytes(decodeHex(t, v.pkd))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := fr.FromBytes(decodeHex(t, v.rcm))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := NoteCommit(gd, pk, v.v, r)
+		if hex.EncodeToString(got.Bytes()) != v.cmu {
+			t.Errorf("vector %d: got cmu %x, want %s", i, got.Bytes(), v.cmu)
+		}
+	}
+}
+
+// TestWindowedPedersenCommitReference checks the commitment against the
+// big.Int backend, with the randomness base derived by grouphash
+func TestWindowedPedersenCommitReference(t *testing.T) {
+	curve := jubjub.NewJubjub()
+	groupHasher, err := grouphash.NewGroupHasher([]byte("Zcash_PH"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rBase, err := groupHasher.FindGroupHash([]byte("r"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasher, err := pedersenhash.NewPedersenHasher()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("windowed Pedersen commitment")
+	rBytes := decodeHex(t, "064456a620a0415423b0e2659b7db333389ab691eb330d2d3e1ed103c47ad908")
+	r, err := fr.FromBytes(rBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	personalization, err := pedersenhash.MerkleTree(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, bitLen := range []int{0, 1, 100, 8 * len(data)} {
+		got, err := WindowedPedersenCommit(personalization, data, bitLen, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bits := make([]bool, bitLen)
+		for i := range bits {
+			bits[i] = (data[i/8]>>(i%8))&1 == 1
+		}
+		h, err := hasher.PedersenHashForBits(personalization.Bits(), bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rSide, err := curve.ScalarMult(leToBig(rBytes), rBase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := curve.Add(h, rSide)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(extended.FromJubjubPoint(want)) {
+			t.Errorf("%d bits: got %s, want %s", bitLen, got, want)
+		}
+	}
+
+	if _, err := WindowedPedersenCommit(pedersenhash.NoteCommitment, data, 8*len(data)+1, r); err != ErrInvalidLength {
+		t.Errorf("expected ErrInvalidLength, got %v", err)
+	}
+}